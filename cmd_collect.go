@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/urlgoscraper"
+)
+
+func runCollectUrls(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("collect-urls", flag.ContinueOnError)
+	sourceFlag := fs.String("source", "all", "comma separated sources to collect (pracuj,nofluff,justjoin)")
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	writeFiles := fs.Bool("write-files", false, "also write the collected urls to url files like older versions did")
+	outDir := fs.String("out-dir", "", "directory for --write-files (default urls_dir from config)")
+	headless := fs.Bool("headless", false, "run the browser headless")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	closeMissing := fs.Bool("close-missing", false, "mark stored offers and urls missing from the fresh listing as closed, only done for the builtin full listings")
+	restart := fs.Bool("restart", false, "collect from the first page instead of resuming an interrupted run")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"out-dir":  func(c *config.Config) { c.URLsDir = *outDir },
+		"headless": func(c *config.Config) { c.Browser.Headless = *headless },
+		"db":       func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	sources, err := parseSources(cfg, *sourceFlag)
+	if err != nil {
+		return err
+	}
+	// boards added in sites_dir have no listing to collect from
+	var selected []source
+	for _, s := range sources {
+		if s.collect == nil {
+			log.Printf("%s: no url collector, list its offers in %s and scrape --from-files", s.name, filepath.Join(cfg.URLsDir, s.urlFile))
+			continue
+		}
+		selected = append(selected, s)
+	}
+
+	// collected urls and collect progress are kept in the database
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	pool, err := fetcher.NewPool(cfg.Proxies)
+	if err != nil {
+		return err
+	}
+	defer pool.LogStats()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(selected))
+	runs := make([]*iternal.Run, len(selected))
+	collected := make([][]string, len(selected))
+	for i, s := range selected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			settings := s.settings(cfg)
+			run, err := iternal.StartCollectRun(ctx, db, s.sourceName, settings.StartURL, *restart)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: starting run: %w", s.name, err)
+				return
+			}
+			if run.Resumed() {
+				log.Printf("%s: resuming interrupted run with %d urls", s.name, len(run.URLs()))
+			}
+
+			urls, err := s.collect(ctx, cfg.Browser, settings, s.site.CaptchaMarkers, pool, run)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.name, err)
+				return
+			}
+			if *writeFiles {
+				path := filepath.Join(cfg.URLsDir, s.urlFile)
+				if err := urlsgocraper.SaveUrls(path, urls); err != nil {
+					errs[i] = fmt.Errorf("%s: writing url file: %w", s.name, err)
+					return
+				}
+				log.Printf("%s: wrote %d urls to %s", s.name, len(urls), path)
+			}
+			runs[i], collected[i] = run, urls
+		}()
+	}
+	wg.Wait()
+
+	errs = append(errs, storeCollected(ctx, db, cfg, selected, runs, collected, errs, *closeMissing))
+	return errors.Join(errs...)
+}
+
+// runs after every listing is in, sqlite takes one writer at a time.
+// collectErrs[i] is the error of collecting selected[i]
+func storeCollected(ctx context.Context, db *sql.DB, cfg *config.Config, selected []source, runs []*iternal.Run, collected [][]string, collectErrs []error, closeMissing bool) error {
+	var errs []error
+	for i, s := range selected {
+		// a filtered start_url lists part of the board, everything outside the filter would get closed
+		closeMissing := closeMissing
+		if full := s.settings(config.Default()).StartURL; closeMissing && s.settings(cfg).StartURL != full {
+			log.Printf("%s: start_url is not the full listing %s, not closing missing offers", s.name, full)
+			closeMissing = false
+		}
+		// failed collects were reported already, a partial listing would close the offers
+		// and urls it missed, their run stays open to resume
+		if collectErrs[i] != nil || collected[i] == nil {
+			continue
+		}
+		added, gone, err := iternal.SaveDiscoveredURLs(ctx, db, s.sourceName, collected[i], closeMissing)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: saving urls: %w", s.name, err))
+			continue
+		}
+		log.Printf("%s: stored %d urls, %d new, %d gone from the listing", s.name, len(collected[i]), added, gone)
+		if err := runs[i].Finish(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: finishing run: %w", s.name, err))
+			continue
+		}
+
+		if !closeMissing {
+			continue
+		}
+		closed, reopened, err := iternal.SyncListedOffers(ctx, db, s.sourceName, collected[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: closing missing offers: %w", s.name, err))
+			continue
+		}
+		log.Printf("%s: closed %d offers missing from the listing, reopened %d", s.name, closed, reopened)
+	}
+	return errors.Join(errs...)
+}
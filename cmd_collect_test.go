@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilteredListingClosesNothing(t *testing.T) {
+	ctx := context.Background()
+	db, err := openDB(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	q := database.New(db)
+
+	agile, other := "https://www.pracuj.pl/praca/scrum-master,oferta,1", "https://www.pracuj.pl/praca/go-developer,oferta,2"
+	for i, url := range []string{agile, other} {
+		_, err := q.UpsertJobOffer(ctx, database.UpsertJobOfferParams{ID: []string{"a", "b"}[i], Title: "offer", Url: url, Source: "pracuj.pl"})
+		require.NoError(t, err)
+	}
+	_, _, err = iternal.SaveDiscoveredURLs(ctx, db, "pracuj.pl", []string{agile, other}, false)
+	require.NoError(t, err)
+
+	pracuj, err := parseSources(config.Default(), "pracuj")
+	require.NoError(t, err)
+	collect := func(cfg *config.Config) {
+		run, err := iternal.StartCollectRun(ctx, db, "pracuj.pl", cfg.Sources.Pracuj.StartURL, true)
+		require.NoError(t, err)
+		require.NoError(t, storeCollected(ctx, db, cfg, pracuj, []*iternal.Run{run}, [][]string{{agile}}, []error{nil}, true))
+	}
+
+	filtered := config.Default()
+	filtered.Sources.Pracuj.StartURL = "https://it.pracuj.pl/praca?its=agile"
+	collect(filtered)
+	stored, err := q.GetJobOfferByUrl(ctx, other)
+	require.NoError(t, err)
+	assert.False(t, stored.ClosedAt.Valid, "offers outside the filter stay open")
+	urls, err := iternal.LoadDiscoveredURLs(ctx, db, "pracuj.pl")
+	require.NoError(t, err)
+	assert.Len(t, urls, 2)
+
+	collect(config.Default())
+	stored, err = q.GetJobOfferByUrl(ctx, other)
+	require.NoError(t, err)
+	assert.True(t, stored.ClosedAt.Valid, "the full listing closes what it no longer lists")
+}
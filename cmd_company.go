@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+)
+
+func runCompany(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("company", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), `usage: jobscraper company [flags] <name>`)
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	skills := fs.Int("skills", 10, "number of top skills to show")
+	format := fs.String("format", "text", "output format (text, json)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	name := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: missing company name", errUsage)
+	}
+	if *skills < 0 {
+		return fmt.Errorf("%w: --skills can't be negative", errUsage)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := openReadOnlyDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	company, err := iternal.CompanySummary(ctx, db, name, *skills)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(company)
+	}
+	fmt.Println(company.Name)
+	var others []string
+	for _, a := range company.Aliases {
+		if a != company.Name {
+			others = append(others, a)
+		}
+	}
+	if len(others) > 0 {
+		fmt.Printf("  also: %s\n", strings.Join(others, ", "))
+	}
+	for _, source := range slices.Sorted(maps.Keys(company.Profiles)) {
+		fmt.Printf("  %s: %s\n", source, company.Profiles[source])
+	}
+	fmt.Printf("  %d open offers, %d jobs\n", company.OpenOffers, company.Jobs)
+	for _, s := range company.Salaries {
+		fmt.Printf("  %s median %.0f–%.0f %s / month (%d jobs)\n", s.Contract, s.MedianMonthlyMin, s.MedianMonthlyMax, s.Currency, s.Offers)
+	}
+	if len(company.TopSkills) > 0 {
+		top := make([]string, len(company.TopSkills))
+		for i, s := range company.TopSkills {
+			top[i] = fmt.Sprintf("%s (%d)", s.Skill, s.Offers)
+		}
+		fmt.Printf("  skills: %s\n", strings.Join(top, ", "))
+	}
+	if len(company.Offers) > 0 {
+		fmt.Println()
+	}
+	for _, o := range company.Offers {
+		fmt.Printf("%s (%s)\n  %s\n", o.Title, o.Source, o.URL)
+	}
+	return nil
+}
+
+// scrape links offers stored before companies existed, a failure only delays it to the next run
+func linkCompanies(ctx context.Context, db *sql.DB) {
+	linked, err := iternal.LinkCompanies(ctx, db)
+	if err != nil {
+		log.Printf("Error %s in linking offers to companies", err)
+		return
+	}
+	if linked > 0 {
+		log.Printf("Linked %d offers to their companies", linked)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+)
+
+func runDedupe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	jobs, duplicates, err := iternal.GroupPostings(ctx, db)
+	if err != nil {
+		return err
+	}
+	log.Printf("Grouped %d offers into %d jobs", jobs+duplicates, jobs)
+	return nil
+}
+
+// scrape groups after every run, a failure only costs the grouping of the new offers
+func groupPostings(ctx context.Context, db *sql.DB) {
+	jobs, duplicates, err := iternal.GroupPostings(ctx, db)
+	if err != nil {
+		log.Printf("Error %s in grouping duplicate offers", err)
+		return
+	}
+	log.Printf("Grouped %d offers into %d jobs", jobs+duplicates, jobs)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/pfczx/jobscraper/iternal/embedding"
+)
+
+func runEmbed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("embed", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+	emb, err := embedding.New(cfg.Embedding)
+	if err != nil {
+		return err
+	}
+	if emb == nil {
+		return errors.New("embedding.provider is none, nothing to embed with")
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	n, err := iternal.EmbedMissing(ctx, db, emb)
+	log.Printf("Embedded %d offers with %s", n, emb.Model())
+	return err
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+)
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	format := fs.String("format", "json", "output format (json, csv)")
+	outPath := fs.String("out", "-", "output file, - for stdout")
+	sourceFlag := fs.String("source", "", "only export offers from one source (pracuj,nofluff,justjoin)")
+	includeClosed := fs.Bool("include-closed", false, "also export offers that were closed")
+	group := fs.Bool("group", false, "export one offer per job, offers of the same job on other boards are listed under postings")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	sourceName := ""
+	if *sourceFlag != "" {
+		selected, err := parseSources(cfg, *sourceFlag)
+		if err != nil {
+			return err
+		}
+		if len(selected) != 1 {
+			return fmt.Errorf("%w: export takes a single source, got %q", errUsage, *sourceFlag)
+		}
+		// the canonical offer of a job may come from another board
+		if *group {
+			return fmt.Errorf("%w: --group covers every source, drop --source", errUsage)
+		}
+		sourceName = selected[0].sourceName
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *outPath != "-" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := iternal.ExportJobOffers(ctx, db, w, *format, sourceName, *includeClosed, *group)
+	if err != nil {
+		return err
+	}
+	log.Printf("Exported %d offers", n)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+)
+
+// longer values (descriptions) are cut in text output, json keeps them whole
+const historyValueWidth = 120
+
+func runHistory(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	id := fs.String("id", "", "offer id")
+	url := fs.String("url", "", "offer url, used instead of --id")
+	format := fs.String("format", "text", "output format (text, json)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if (*id == "") == (*url == "") {
+		return fmt.Errorf("%w: pass exactly one of --id and --url", errUsage)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := openReadOnlyDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	offerID := *id
+	if *url != "" {
+		if offerID, err = iternal.OfferIDByURL(ctx, db, *url); err != nil {
+			return err
+		}
+	}
+	history, err := iternal.OfferHistory(ctx, db, offerID)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(history)
+	}
+	if len(history) == 0 {
+		fmt.Println("no changes recorded")
+	}
+	for _, v := range history {
+		fmt.Printf("version %d, %s\n", v.Version, v.ChangedAt.Format("2006-01-02 15:04"))
+		for _, c := range v.Changes {
+			fmt.Printf("  %s\n    - %s\n    + %s\n", c.Field, shorten(c.Old), shorten(c.New))
+		}
+	}
+	return nil
+}
+
+func shorten(s string) string {
+	r := []rune(s)
+	if len(r) <= historyValueWidth {
+		return s
+	}
+	return string(r[:historyValueWidth]) + "…"
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/migrate"
+	"github.com/pfczx/jobscraper/sql/schema"
+)
+
+func runMigrate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), `usage: jobscraper migrate [flags] up|down|status`)
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: pass one of up, down, status", errUsage)
+	}
+	action := fs.Arg(0)
+	if action != "up" && action != "down" && action != "status" {
+		return fmt.Errorf("%w: unknown migrate action %q", errUsage, action)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+	set, err := migrate.Load(schema.FS)
+	if err != nil {
+		return err
+	}
+
+	// not openDB, it would migrate up before down or status get to look
+	db, err := openDSN(cfg.DBPath, cfg.DBPath+"?_foreign_keys=on")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch action {
+	case "up":
+		applied, err := set.Up(ctx, db)
+		for _, m := range applied {
+			log.Printf("Applied migration %s", m)
+		}
+		if err == nil && len(applied) == 0 {
+			log.Println("Database is up to date")
+		}
+		return err
+	case "down":
+		// a broken down section must fail on an empty database, not halfway through this one
+		if err := set.Validate(ctx); err != nil {
+			return fmt.Errorf("migrations don't revert cleanly: %w", err)
+		}
+		m, err := set.Down(ctx, db)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			log.Println("No migration to revert")
+			return nil
+		}
+		log.Printf("Reverted migration %s", m)
+		return nil
+	}
+
+	states, err := set.Status(ctx, db)
+	for _, s := range states {
+		switch {
+		case s.Applied:
+			fmt.Printf("%-40s applied %s\n", s.Name, s.AppliedAt.Local().Format("2006-01-02 15:04"))
+		case s.Skipped != "":
+			fmt.Printf("%-40s skipped, %s\n", s.Name, s.Skipped)
+		default:
+			fmt.Printf("%-40s pending\n", s.Name)
+		}
+	}
+	return err
+}
+
+// brings db to the schema of this binary, refusing databases a newer one migrated
+func migrateUp(ctx context.Context, db *sql.DB) error {
+	set, err := migrate.Load(schema.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := set.Up(ctx, db)
+	for _, m := range applied {
+		log.Printf("Applied migration %s", m)
+	}
+	return err
+}
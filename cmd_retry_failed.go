@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+func runRetryFailed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("retry-failed", flag.ContinueOnError)
+	sourceFlag := fs.String("source", "all", "comma separated sources to retry (pracuj,nofluff,justjoin)")
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	parallel := fs.Bool("parallel", false, "run scrapers in parallel")
+	headless := fs.Bool("headless", false, "run the browser headless")
+	force := fs.Bool("force", false, "retry transient failures now, ignoring their backoff")
+	list := fs.Bool("list", false, "only print the failed urls")
+	format := fs.String("format", "text", "output format of --list (text, json)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db":       func(c *config.Config) { c.DBPath = *dbPath },
+		"parallel": func(c *config.Config) { c.Scrape.Parallel = *parallel },
+		"headless": func(c *config.Config) { c.Browser.Headless = *headless },
+	})
+	if err != nil {
+		return err
+	}
+
+	selected, err := parseSources(cfg, *sourceFlag)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if *list {
+		var failed []iternal.FailedURL
+		for _, s := range selected {
+			urls, err := iternal.ListFailedURLs(ctx, db, s.sourceName)
+			if err != nil {
+				return err
+			}
+			failed = append(failed, urls...)
+		}
+		return printFailedURLs(failed, cfg.Scrape.RetryFailed, *format)
+	}
+
+	pool, err := fetcher.NewPool(cfg.Proxies)
+	if err != nil {
+		return err
+	}
+	defer pool.LogStats()
+
+	captchas := scraper.CaptchasFromConfig(cfg.Scrape.Captcha, os.Stdin)
+	defer captchas.LogCounts()
+
+	backoff := cfg.Scrape.RetryFailed.Backoff
+	if *force {
+		backoff = 0
+	}
+	started := time.Now()
+	stats := scraper.NewStats()
+	var scrapersList []scraper.Scraper
+	for _, s := range selected {
+		due, err := iternal.DueFailedURLs(ctx, db, s.sourceName, backoff, cfg.Scrape.RetryFailed.MaxAttempts, started)
+		if err != nil {
+			return fmt.Errorf("%s: loading failed urls: %w", s.name, err)
+		}
+		if len(due) == 0 {
+			log.Printf("%s: no failed urls due for a retry", s.name)
+			continue
+		}
+		log.Printf("%s: retrying %d failed urls", s.name, len(due))
+
+		urls := make([]string, 0, len(due))
+		for _, f := range due {
+			urls = append(urls, f.URL)
+		}
+		f := fetcher.New(ctx, cfg.Browser, s.settings(cfg), s.settings(cfg).ScrapeRateLimit(), s.site.CaptchaMarkers, pool)
+		defer f.Close()
+		scrapersList = append(scrapersList, s.newScraper(f, s.settings(cfg), urls, captchas, stats))
+	}
+	if len(scrapersList) == 0 {
+		return nil
+	}
+
+	if err := collectOffers(ctx, cfg, db, scrapersList, nil, stats, started); err != nil {
+		return err
+	}
+	log.Println("Retry Completed")
+	return nil
+}
+
+func printFailedURLs(failed []iternal.FailedURL, retry config.RetryFailed, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(failed)
+	}
+	if len(failed) == 0 {
+		fmt.Println("no failed urls")
+	}
+	for _, f := range failed {
+		state := "next retry " + f.NextAttempt(retry.Backoff).Local().Format("2006-01-02 15:04")
+		switch {
+		case f.Permanent:
+			state = "permanent"
+		case f.Attempts >= retry.MaxAttempts:
+			state = "given up"
+		}
+		fmt.Printf("%s (%s), %d attempts, %s\n  %s\n", f.URL, f.Source, f.Attempts, state, shorten(f.Error))
+	}
+	return nil
+}
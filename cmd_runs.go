@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+)
+
+func runRuns(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("runs", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	limit := fs.Int("limit", 10, "number of recent runs to show")
+	format := fs.String("format", "text", "output format (text, json)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *limit < 1 {
+		return fmt.Errorf("%w: --limit must be at least 1", errUsage)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+
+	db, err := openReadOnlyDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	runs, err := iternal.RecentRuns(ctx, db, *limit)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+	if len(runs) == 0 {
+		fmt.Println("no runs recorded")
+	}
+	for _, r := range runs {
+		status := "completed"
+		if r.Error != "" {
+			status = "failed: " + r.Error
+		}
+		fmt.Printf("run %d, %s, took %s, %s\n", r.ID, r.StartedAt.Local().Format("2006-01-02 15:04"), r.Duration.Round(time.Second), status)
+		for _, s := range r.Sources {
+			fmt.Printf("  %-16s %5d attempted %5d saved %4d closed %4d fetch failed %4d parse failed %4d save failed %4d captchas\n",
+				s.Source, s.Attempted, s.Saved, s.Closed, s.FetchFailed, s.ParseFailed, s.SaveFailed, s.Captchas)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/pfczx/jobscraper/iternal/embedding"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
+	"github.com/pfczx/jobscraper/urlgoscraper"
+)
+
+// opens the database with every migration of this binary applied
+func openDB(path string) (*sql.DB, error) {
+	db, err := openDSN(path, path+"?_foreign_keys=on")
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateUp(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating %s: %w", path, err)
+	}
+	indexed, err := iternal.RebuildSearchIndex(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("rebuilding search index of %s: %w", path, err)
+	}
+	if indexed > 0 {
+		log.Printf("Rebuilt search index of %d offers", indexed)
+	}
+	return db, nil
+}
+
+// query_only makes sqlite refuse every write on the connection, so migrations run on a connection of their own first
+func openReadOnlyDB(path string) (*sql.DB, error) {
+	db, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+	db.Close()
+	return openDSN(path, path+"?_foreign_keys=on&_query_only=on")
+}
+
+func openDSN(path, dsn string) (*sql.DB, error) {
+	// sqlite creates the file but not its directory
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func runScrape(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	sourceFlag := fs.String("source", "all", "comma separated sources to scrape (pracuj,nofluff,justjoin)")
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	fromFiles := fs.Bool("from-files", false, "scrape the urls of the url files instead of the collected ones, importing them first")
+	urlsDir := fs.String("urls-dir", "", "directory for --from-files (default urls_dir from config)")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	parallel := fs.Bool("parallel", false, "run scrapers in parallel")
+	headless := fs.Bool("headless", false, "run the browser headless")
+	rescrapeAfter := fs.Duration("rescrape-after", 0, "skip offers scraped within this time, 0 scrapes every url (default rescrape_after from config)")
+	restart := fs.Bool("restart", false, "scrape every url again instead of resuming an interrupted run")
+	captcha := fs.String("captcha", "", "what to do on captcha pages: pause, requeue or abort (default scrape.captcha.strategy from config)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"urls-dir":       func(c *config.Config) { c.URLsDir = *urlsDir },
+		"db":             func(c *config.Config) { c.DBPath = *dbPath },
+		"parallel":       func(c *config.Config) { c.Scrape.Parallel = *parallel },
+		"rescrape-after": func(c *config.Config) { c.Scrape.RescrapeAfter = *rescrapeAfter },
+		"captcha":        func(c *config.Config) { c.Scrape.Captcha.Strategy = *captcha },
+		"headless":       func(c *config.Config) { c.Browser.Headless = *headless },
+	})
+	if err != nil {
+		return err
+	}
+
+	selected, err := parseSources(cfg, *sourceFlag)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	pool, err := fetcher.NewPool(cfg.Proxies)
+	if err != nil {
+		return err
+	}
+	defer pool.LogStats()
+
+	// pause prompts share stdin, only one scraper asks at a time
+	captchas := scraper.CaptchasFromConfig(cfg.Scrape.Captcha, os.Stdin)
+	defer captchas.LogCounts()
+
+	// every selected source gets a history row, even with nothing left to scrape
+	started := time.Now()
+	names := make([]string, 0, len(selected))
+	for _, s := range selected {
+		names = append(names, s.sourceName)
+	}
+	stats := scraper.NewStats(names...)
+	var scrapersList []scraper.Scraper
+	runs := iternal.Runs{}
+	for _, s := range selected {
+		urls, err := scrapeURLs(ctx, db, cfg, s, *fromFiles)
+		if err != nil {
+			return fmt.Errorf("%s: loading urls: %w", s.name, err)
+		}
+
+		listed := urls
+		if cfg.Scrape.RescrapeAfter > 0 {
+			if urls, err = iternal.SelectStale(ctx, db, s.sourceName, urls, cfg.Scrape.RescrapeAfter); err != nil {
+				return fmt.Errorf("%s: checking freshness: %w", s.name, err)
+			}
+			log.Printf("%s: %d of %d urls not scraped within %s", s.name, len(urls), len(listed), cfg.Scrape.RescrapeAfter)
+		}
+		stale := len(urls)
+		retry := cfg.Scrape.RetryFailed
+		if urls, err = iternal.SkipFailed(ctx, db, s.sourceName, urls, retry.Backoff, retry.MaxAttempts, started); err != nil {
+			return fmt.Errorf("%s: loading failed urls: %w", s.name, err)
+		}
+		if skipped := stale - len(urls); skipped > 0 {
+			log.Printf("%s: skipping %d failed urls not due for a retry", s.name, skipped)
+		}
+
+		run, err := iternal.StartScrapeRun(ctx, db, s.sourceName, listed, urls, *restart)
+		if err != nil {
+			return fmt.Errorf("%s: starting run: %w", s.name, err)
+		}
+		if run.Resumed() {
+			log.Printf("%s: resuming interrupted run, %d of %d urls left", s.name, run.Left(), len(urls))
+		}
+		runs[s.sourceName] = run
+
+		f := fetcher.New(ctx, cfg.Browser, s.settings(cfg), s.settings(cfg).ScrapeRateLimit(), s.site.CaptchaMarkers, pool)
+		defer f.Close()
+		scrapersList = append(scrapersList, s.newScraper(f, s.settings(cfg), run.Pending(), captchas, stats))
+	}
+
+	defer logRunsLeft(selected, runs)
+	if err := collectOffers(ctx, cfg, db, scrapersList, runs, stats, started); err != nil {
+		return err
+	}
+	log.Println("Scraping Completed")
+	return nil
+}
+
+// runs the scrapers into the database and records the run in the history
+func collectOffers(ctx context.Context, cfg *config.Config, db *sql.DB, scrapersList []scraper.Scraper, runs iternal.Runs, stats *scraper.Stats, started time.Time) error {
+	emb, err := embedding.New(cfg.Embedding)
+	if err != nil {
+		return err
+	}
+
+	scraper.ParallelStartDelay = cfg.Scrape.StartDelay
+	err = iternal.StartCollector(ctx, db, scrapersList, cfg.Scrape.Parallel, skills.New(cfg.Skills.Aliases), emb, runs, stats)
+	// interrupted and failed runs are recorded too, ctrl+c must not stop the write
+	if _, saveErr := iternal.SaveRunHistory(context.WithoutCancel(ctx), db, started, time.Now(), stats.Sources(), err); saveErr != nil {
+		log.Printf("Error %s in saving run history", saveErr)
+	}
+	// an interrupted run leaves linking and grouping to the next one or to dedupe
+	if ctx.Err() == nil {
+		linkCompanies(ctx, db)
+		groupPostings(ctx, db)
+	}
+	return err
+}
+
+// the urls collect-urls stored for s, or the ones of its url file when fromFiles
+func scrapeURLs(ctx context.Context, db *sql.DB, cfg *config.Config, s source, fromFiles bool) ([]string, error) {
+	if !fromFiles {
+		urls, err := iternal.LoadDiscoveredURLs(ctx, db, s.sourceName)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("%s: loaded %d collected urls", s.name, len(urls))
+		return urls, nil
+	}
+
+	path := filepath.Join(cfg.URLsDir, s.urlFile)
+	urls, err := urlsgocraper.LoadUrls(path)
+	if err != nil {
+		return nil, err
+	}
+	// the file may be partial, it closes nothing
+	added, _, err := iternal.SaveDiscoveredURLs(ctx, db, s.sourceName, urls, false)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("%s: loaded %d urls from %s, %d not collected before", s.name, len(urls), path, added)
+	return urls, nil
+}
+
+// urls not reached or not filed as failed are scraped by the next run
+func logRunsLeft(selected []source, runs iternal.Runs) {
+	for _, s := range selected {
+		if left := runs[s.sourceName].Left(); left > 0 {
+			log.Printf("%s: %d urls left, the next scrape resumes them (--restart to start over)", s.name, left)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal"
+	"github.com/pfczx/jobscraper/iternal/embedding"
+)
+
+func runSimilar(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("similar", flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default $JOBSCRAPER_CONFIG or ./"+config.DefaultFile+")")
+	dbPath := fs.String("db", "", "sqlite database path (default db_path from config)")
+	text := fs.String("text", "", "find offers similar to this text")
+	id := fs.String("id", "", "find offers similar to the stored offer with this id")
+	limit := fs.Int("limit", 10, "maximum number of results")
+	format := fs.String("format", "text", "output format (text, json)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if (*text == "") == (*id == "") {
+		return fmt.Errorf("%w: pass exactly one of --text and --id", errUsage)
+	}
+	if *limit < 1 {
+		return fmt.Errorf("%w: --limit must be at least 1", errUsage)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("%w: unknown format %q", errUsage, *format)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, map[string]func(*config.Config){
+		"db": func(c *config.Config) { c.DBPath = *dbPath },
+	})
+	if err != nil {
+		return err
+	}
+	emb, err := embedding.New(cfg.Embedding)
+	if err != nil {
+		return err
+	}
+	if emb == nil {
+		return errors.New("embedding.provider is none, similar needs an embedder")
+	}
+
+	db, err := openDB(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var results []iternal.SimilarResult
+	if *id != "" {
+		results, err = iternal.SimilarToOffer(ctx, db, emb, *id, *limit)
+	} else {
+		results, err = iternal.SimilarToText(ctx, db, emb, *text, *limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	for _, r := range results {
+		fmt.Printf("%.3f  %s - %s (%s)\n       %s\n", r.Score, r.Offer.Title, r.Offer.Company, r.Offer.Source, r.Offer.URL)
+	}
+	if len(results) == 0 {
+		fmt.Println("no similar offers, offers scraped before embeddings need 'jobscraper embed'")
+	}
+	return nil
+}
@@ -0,0 +1,490 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FetcherChrome = "chrome"
+	FetcherHTTP   = "http"
+)
+
+const (
+	EmbedderHash = "hash"
+	EmbedderHTTP = "http"
+	EmbedderNone = "none"
+)
+
+const (
+	RotatePerRequest = "request"
+	RotatePerSession = "session"
+)
+
+const (
+	CaptchaPause   = "pause"
+	CaptchaRequeue = "requeue"
+	CaptchaAbort   = "abort"
+)
+
+// file picked up from the working directory when no --config / JOBSCRAPER_CONFIG is given
+const DefaultFile = "jobscraper.yaml"
+
+type Config struct {
+	DBPath  string `yaml:"db_path"`
+	URLsDir string `yaml:"urls_dir"`
+	// site definitions fixing the builtin boards or adding new ones, empty uses the builtin ones only
+	SitesDir  string    `yaml:"sites_dir"`
+	Browser   Browser   `yaml:"browser"`
+	Scrape    Scrape    `yaml:"scrape"`
+	Sources   Sources   `yaml:"sources"`
+	Skills    Skills    `yaml:"skills"`
+	Embedding Embedding `yaml:"embedding"`
+	Proxies   Proxies   `yaml:"proxies"`
+}
+
+// empty ExecPath lets chromedp find chrome itself
+type Browser struct {
+	ExecPath  string `yaml:"exec_path"`
+	Headless  bool   `yaml:"headless"`
+	UserAgent string `yaml:"user_agent"`
+}
+
+type Scrape struct {
+	Parallel bool `yaml:"parallel"`
+	// delay between starting scrapers in parallel mode
+	StartDelay time.Duration `yaml:"start_delay"`
+	// offers scraped more recently are skipped, 0 scrapes every url
+	RescrapeAfter time.Duration `yaml:"rescrape_after"`
+	Captcha       Captcha       `yaml:"captcha"`
+	RetryFailed   RetryFailed   `yaml:"retry_failed"`
+}
+
+// pacing of retry-failed: a failed url waits Backoff after its first failure, twice as long
+// after every further one, and is given up after MaxAttempts
+type RetryFailed struct {
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxAttempts int           `yaml:"max_attempts"`
+}
+
+// what a scraper does when an offer page turns out to be a captcha.
+// "pause" waits up to Timeout for enter (solve it in the browser), "requeue" moves the url
+// to the end of the list, "abort" stops the source
+type Captcha struct {
+	Strategy string        `yaml:"strategy"`
+	Timeout  time.Duration `yaml:"timeout"`
+	// captcha pages per url before it is skipped until the next run
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// outgoing proxies shared by every source, no urls goes direct.
+// "request" rotates the http fetcher on every page, "session" keeps a proxy until it fails,
+// chrome always proxies a whole browser session and can't use credentials in the url
+type Proxies struct {
+	URLs   []string `yaml:"urls"`
+	Rotate string   `yaml:"rotate"`
+	// failures in a row before a proxy sits out for cooldown
+	MaxFailures int           `yaml:"max_failures"`
+	Cooldown    time.Duration `yaml:"cooldown"`
+}
+
+// extends the builtin skill dictionary, canonical name to aliases
+type Skills struct {
+	Aliases map[string][]string `yaml:"aliases"`
+}
+
+// "hash" embeds offline with hashed bag of words, "http" calls an openai compatible /embeddings endpoint
+type Embedding struct {
+	Provider string `yaml:"provider"`
+	// vector size of the hash embedder
+	Dims    int           `yaml:"dims"`
+	URL     string        `yaml:"url"`
+	Model   string        `yaml:"model"`
+	APIKey  string        `yaml:"api_key"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type Sources struct {
+	Pracuj   Source `yaml:"pracuj"`
+	Nofluff  Source `yaml:"nofluff"`
+	Justjoin Source `yaml:"justjoin"`
+	// boards added in sites_dir, by site name, unset values come from DefaultSource
+	Other map[string]Source `yaml:",inline"`
+}
+
+// UnmarshalYAML decodes an added board over DefaultSource like the builtin ones over Default
+func (s *Sources) UnmarshalYAML(node *yaml.Node) error {
+	var nodes map[string]yaml.Node
+	if err := node.Decode(&nodes); err != nil {
+		return err
+	}
+	for name, n := range nodes {
+		var target *Source
+		switch name {
+		case "pracuj":
+			target = &s.Pracuj
+		case "nofluff":
+			target = &s.Nofluff
+		case "justjoin":
+			target = &s.Justjoin
+		default:
+			if s.Other == nil {
+				s.Other = map[string]Source{}
+			}
+			source, ok := s.Other[name]
+			if !ok {
+				source = DefaultSource()
+			}
+			if err := n.Decode(&source); err != nil {
+				return err
+			}
+			s.Other[name] = source
+			continue
+		}
+		if err := n.Decode(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Source is the settings of an added board, DefaultSource when the config has none for it
+func (s Sources) Source(name string) Source {
+	if source, ok := s.Other[name]; ok {
+		return source
+	}
+	return DefaultSource()
+}
+
+// per job board settings, requests to a board are spaced between min and max delay,
+// see RateLimit
+type Source struct {
+	StartURL string `yaml:"start_url"`
+	// "chrome" (default) or "http" for boards that render without javascript
+	Fetcher string `yaml:"fetcher"`
+	// chrome profile dir, keeps cookies/cloudflare clearance between runs, empty uses a temp profile
+	DataDir         string        `yaml:"data_dir"`
+	MinDelay        time.Duration `yaml:"min_delay"`
+	MaxDelay        time.Duration `yaml:"max_delay"`
+	CollectMinDelay time.Duration `yaml:"collect_min_delay"`
+	CollectMaxDelay time.Duration `yaml:"collect_max_delay"`
+	Retries         int           `yaml:"retries"`
+	// longest spacing after 429/403 or captcha pages, 0 never backs off
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// requests allowed back to back before the delays kick in
+	Burst int `yaml:"burst"`
+}
+
+// RateLimit paces requests to one host: spacing starts at MaxDelay, shrinks toward
+// MinDelay while the site answers fine and doubles up to MaxBackoff when it pushes back
+type RateLimit struct {
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+	MaxBackoff time.Duration
+	Burst      int
+}
+
+// pacing of offer pages
+func (s Source) ScrapeRateLimit() RateLimit {
+	return RateLimit{MinDelay: s.MinDelay, MaxDelay: s.MaxDelay, MaxBackoff: s.MaxBackoff, Burst: s.Burst}
+}
+
+// pacing of listing pages and scrolls while collecting urls
+func (s Source) CollectRateLimit() RateLimit {
+	return RateLimit{MinDelay: s.CollectMinDelay, MaxDelay: s.CollectMaxDelay, MaxBackoff: s.MaxBackoff, Burst: s.Burst}
+}
+
+// DefaultSource is the pacing of a board added in sites_dir, start_url is only needed to collect
+func DefaultSource() Source {
+	return Source{
+		Fetcher:         FetcherChrome,
+		MinDelay:        5 * time.Second,
+		MaxDelay:        10 * time.Second,
+		CollectMinDelay: 5 * time.Second,
+		CollectMaxDelay: 10 * time.Second,
+		Retries:         3,
+		MaxBackoff:      5 * time.Minute,
+		Burst:           1,
+	}
+}
+
+func Default() *Config {
+	return &Config{
+		DBPath:  "./database/jobs.db",
+		URLsDir: ".",
+		Browser: Browser{
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) " +
+				"AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+		},
+		Scrape: Scrape{
+			StartDelay:  5 * time.Second,
+			Captcha:     Captcha{Strategy: CaptchaPause, Timeout: 5 * time.Minute, MaxAttempts: 3},
+			RetryFailed: RetryFailed{Backoff: time.Hour, MaxAttempts: 5},
+		},
+		Proxies: Proxies{
+			Rotate:      RotatePerRequest,
+			MaxFailures: 3,
+			Cooldown:    5 * time.Minute,
+		},
+		Embedding: Embedding{
+			Provider: EmbedderHash,
+			Dims:     512,
+			Timeout:  30 * time.Second,
+		},
+		Sources: Sources{
+			Pracuj: Source{
+				StartURL:        "https://it.pracuj.pl/praca",
+				Fetcher:         FetcherChrome,
+				MinDelay:        5 * time.Second,
+				MaxDelay:        10 * time.Second,
+				CollectMinDelay: 5 * time.Second,
+				CollectMaxDelay: 10 * time.Second,
+				Retries:         3,
+				MaxBackoff:      5 * time.Minute,
+				Burst:           1,
+			},
+			Nofluff: Source{
+				StartURL:        "https://nofluffjobs.com/pl/",
+				Fetcher:         FetcherChrome,
+				MinDelay:        5 * time.Second,
+				MaxDelay:        10 * time.Second,
+				CollectMinDelay: 3 * time.Second,
+				CollectMaxDelay: 4 * time.Second,
+				Retries:         3,
+				MaxBackoff:      5 * time.Minute,
+				Burst:           1,
+			},
+			Justjoin: Source{
+				StartURL:        "https://justjoin.it/job-offers/",
+				Fetcher:         FetcherChrome,
+				MinDelay:        5 * time.Second,
+				MaxDelay:        10 * time.Second,
+				CollectMinDelay: 3 * time.Second,
+				CollectMaxDelay: 4 * time.Second,
+				Retries:         3,
+				MaxBackoff:      5 * time.Minute,
+				Burst:           1,
+			},
+		},
+	}
+}
+
+// Load builds config from defaults, the yaml file and JOBSCRAPER_* env vars, in that order.
+// Empty path falls back to JOBSCRAPER_CONFIG and then to DefaultFile if it exists.
+// Result is not validated so callers can apply flags first.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	required := path != ""
+	if path == "" {
+		path = os.Getenv("JOBSCRAPER_CONFIG")
+		required = path != ""
+	}
+	if path == "" {
+		path = DefaultFile
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"JOBSCRAPER_DB_PATH":            &c.DBPath,
+		"JOBSCRAPER_URLS_DIR":           &c.URLsDir,
+		"JOBSCRAPER_SITES_DIR":          &c.SitesDir,
+		"JOBSCRAPER_BROWSER_PATH":       &c.Browser.ExecPath,
+		"JOBSCRAPER_USER_AGENT":         &c.Browser.UserAgent,
+		"JOBSCRAPER_PRACUJ_DATA_DIR":    &c.Sources.Pracuj.DataDir,
+		"JOBSCRAPER_NOFLUFF_DATA_DIR":   &c.Sources.Nofluff.DataDir,
+		"JOBSCRAPER_JUSTJOIN_DATA_DIR":  &c.Sources.Justjoin.DataDir,
+		"JOBSCRAPER_PRACUJ_START_URL":   &c.Sources.Pracuj.StartURL,
+		"JOBSCRAPER_NOFLUFF_START_URL":  &c.Sources.Nofluff.StartURL,
+		"JOBSCRAPER_JUSTJOIN_START_URL": &c.Sources.Justjoin.StartURL,
+		"JOBSCRAPER_EMBEDDING_URL":      &c.Embedding.URL,
+		"JOBSCRAPER_EMBEDDING_API_KEY":  &c.Embedding.APIKey,
+	}
+	for key, field := range strs {
+		if v, ok := lookup(key); ok {
+			*field = v
+		}
+	}
+
+	bools := map[string]*bool{
+		"JOBSCRAPER_HEADLESS": &c.Browser.Headless,
+		"JOBSCRAPER_PARALLEL": &c.Scrape.Parallel,
+	}
+	for key, field := range bools {
+		if v, ok := lookup(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			*field = b
+		}
+	}
+	return nil
+}
+
+func (s Source) validate(name string, needsStartURL bool) []error {
+	var errs []error
+	if u, err := url.Parse(s.StartURL); (needsStartURL || s.StartURL != "") && (err != nil || u.Scheme == "" || u.Host == "") {
+		errs = append(errs, fmt.Errorf("sources.%s.start_url: invalid url %q", name, s.StartURL))
+	}
+	if s.Fetcher != FetcherChrome && s.Fetcher != FetcherHTTP {
+		errs = append(errs, fmt.Errorf("sources.%s.fetcher: must be %q or %q, got %q", name, FetcherChrome, FetcherHTTP, s.Fetcher))
+	}
+	if s.MinDelay < 0 || s.MaxDelay < s.MinDelay {
+		errs = append(errs, fmt.Errorf("sources.%s: need 0 <= min_delay <= max_delay, got %s and %s", name, s.MinDelay, s.MaxDelay))
+	}
+	if s.CollectMinDelay < 0 || s.CollectMaxDelay < s.CollectMinDelay {
+		errs = append(errs, fmt.Errorf("sources.%s: need 0 <= collect_min_delay <= collect_max_delay, got %s and %s", name, s.CollectMinDelay, s.CollectMaxDelay))
+	}
+	if s.Retries < 1 {
+		errs = append(errs, fmt.Errorf("sources.%s.retries: must be at least 1, got %d", name, s.Retries))
+	}
+	if s.MaxBackoff != 0 && (s.MaxBackoff < s.MaxDelay || s.MaxBackoff < s.CollectMaxDelay) {
+		errs = append(errs, fmt.Errorf("sources.%s.max_backoff: must be 0 or at least max_delay and collect_max_delay, got %s", name, s.MaxBackoff))
+	}
+	if s.Burst < 1 {
+		errs = append(errs, fmt.Errorf("sources.%s.burst: must be at least 1, got %d", name, s.Burst))
+	}
+	return errs
+}
+
+func (e Embedding) validate() []error {
+	var errs []error
+	switch e.Provider {
+	case EmbedderNone:
+	case EmbedderHash:
+		if e.Dims < 1 {
+			errs = append(errs, fmt.Errorf("embedding.dims: must be at least 1, got %d", e.Dims))
+		}
+	case EmbedderHTTP:
+		if u, err := url.Parse(e.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("embedding.url: invalid url %q", e.URL))
+		}
+		if e.Model == "" {
+			errs = append(errs, errors.New("embedding.model: must be set for the http provider"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("embedding.provider: must be %q, %q or %q, got %q", EmbedderHash, EmbedderHTTP, EmbedderNone, e.Provider))
+	}
+	if e.Timeout < 0 {
+		errs = append(errs, errors.New("embedding.timeout: must not be negative"))
+	}
+	return errs
+}
+
+func (c Captcha) validate() []error {
+	var errs []error
+	switch c.Strategy {
+	case CaptchaPause, CaptchaRequeue, CaptchaAbort:
+	default:
+		errs = append(errs, fmt.Errorf("scrape.captcha.strategy: must be %q, %q or %q, got %q", CaptchaPause, CaptchaRequeue, CaptchaAbort, c.Strategy))
+	}
+	if c.Strategy == CaptchaPause && c.Timeout <= 0 {
+		errs = append(errs, errors.New("scrape.captcha.timeout: must be positive"))
+	}
+	if c.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("scrape.captcha.max_attempts: must be at least 1, got %d", c.MaxAttempts))
+	}
+	return errs
+}
+
+func (p Proxies) validate() []error {
+	var errs []error
+	for _, raw := range p.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			errs = append(errs, fmt.Errorf("proxies.urls: %q must be an http, https or socks5 url", raw))
+		}
+	}
+	if p.Rotate != RotatePerRequest && p.Rotate != RotatePerSession {
+		errs = append(errs, fmt.Errorf("proxies.rotate: must be %q or %q, got %q", RotatePerRequest, RotatePerSession, p.Rotate))
+	}
+	if p.MaxFailures < 1 {
+		errs = append(errs, fmt.Errorf("proxies.max_failures: must be at least 1, got %d", p.MaxFailures))
+	}
+	if p.Cooldown < 0 {
+		errs = append(errs, errors.New("proxies.cooldown: must not be negative"))
+	}
+	return errs
+}
+
+// Validate reports every problem at once so a broken config fails before any browser starts
+func (c *Config) Validate() error {
+	var errs []error
+	if c.DBPath == "" {
+		errs = append(errs, errors.New("db_path: must not be empty"))
+	}
+	if c.URLsDir == "" {
+		errs = append(errs, errors.New("urls_dir: must not be empty"))
+	}
+	if c.SitesDir != "" {
+		if info, err := os.Stat(c.SitesDir); err != nil {
+			errs = append(errs, fmt.Errorf("sites_dir: %w", err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("sites_dir: %s is not a directory", c.SitesDir))
+		}
+	}
+	if c.Browser.ExecPath != "" {
+		if _, err := os.Stat(c.Browser.ExecPath); err != nil {
+			errs = append(errs, fmt.Errorf("browser.exec_path: %w", err))
+		}
+	}
+	if c.Scrape.StartDelay < 0 {
+		errs = append(errs, errors.New("scrape.start_delay: must not be negative"))
+	}
+	if c.Scrape.RescrapeAfter < 0 {
+		errs = append(errs, errors.New("scrape.rescrape_after: must not be negative"))
+	}
+	errs = append(errs, c.Scrape.Captcha.validate()...)
+	if c.Scrape.RetryFailed.Backoff < 0 {
+		errs = append(errs, errors.New("scrape.retry_failed.backoff: must not be negative"))
+	}
+	if c.Scrape.RetryFailed.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("scrape.retry_failed.max_attempts: must be at least 1, got %d", c.Scrape.RetryFailed.MaxAttempts))
+	}
+	errs = append(errs, c.Sources.Pracuj.validate("pracuj", true)...)
+	errs = append(errs, c.Sources.Nofluff.validate("nofluff", true)...)
+	errs = append(errs, c.Sources.Justjoin.validate("justjoin", true)...)
+	for _, name := range slices.Sorted(maps.Keys(c.Sources.Other)) {
+		errs = append(errs, c.Sources.Other[name].validate(name, false)...)
+	}
+	errs = append(errs, c.Embedding.validate()...)
+	errs = append(errs, c.Proxies.validate()...)
+	for canonical, aliases := range c.Skills.Aliases {
+		if strings.TrimSpace(canonical) == "" {
+			errs = append(errs, errors.New("skills.aliases: canonical name must not be empty"))
+		}
+		for _, a := range aliases {
+			if strings.TrimSpace(a) == "" {
+				errs = append(errs, fmt.Errorf("skills.aliases.%s: alias must not be empty", canonical))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobscraper.yaml")
+	content := `
+db_path: /tmp/jobs.db
+scrape:
+  rescrape_after: 72h
+sources:
+  pracuj:
+    min_delay: 1s
+    max_delay: 2s
+  bulldogjob:
+    fetcher: http
+    retries: 5
+skills:
+  aliases:
+    Airflow: [apache airflow]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	t.Setenv("JOBSCRAPER_DB_PATH", "/data/jobs.db")
+	t.Setenv("JOBSCRAPER_HEADLESS", "true")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/data/jobs.db", cfg.DBPath, "env should win over file")
+	assert.True(t, cfg.Browser.Headless)
+	assert.Equal(t, time.Second, cfg.Sources.Pracuj.MinDelay)
+	assert.Equal(t, 2*time.Second, cfg.Sources.Pracuj.MaxDelay)
+	assert.Equal(t, 72*time.Hour, cfg.Scrape.RescrapeAfter)
+	// untouched values keep defaults
+	assert.Equal(t, 3, cfg.Sources.Pracuj.Retries)
+	assert.Equal(t, "https://nofluffjobs.com/pl/", cfg.Sources.Nofluff.StartURL)
+	assert.Equal(t, []string{"apache airflow"}, cfg.Skills.Aliases["Airflow"])
+	// a board added in sites_dir starts from the default pacing
+	added := cfg.Sources.Source("bulldogjob")
+	assert.Equal(t, FetcherHTTP, added.Fetcher)
+	assert.Equal(t, 5, added.Retries)
+	assert.Equal(t, 10*time.Second, added.MaxDelay)
+	assert.Equal(t, DefaultSource(), cfg.Sources.Source("nofluffjobs"))
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadMissingExplicitFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "nope.yaml"))
+	assert.Error(t, err)
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.DBPath = ""
+	cfg.Sources.Nofluff.MinDelay = 10 * time.Second
+	cfg.Sources.Nofluff.MaxDelay = time.Second
+	cfg.Sources.Justjoin.Retries = 0
+	cfg.Sources.Pracuj.StartURL = "not a url"
+	cfg.Embedding.Provider = "http"
+	cfg.Scrape.RescrapeAfter = -time.Hour
+	cfg.Proxies.URLs = []string{"ftp://proxy:21"}
+	cfg.Scrape.Captcha.Strategy = "ignore"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "db_path")
+	assert.ErrorContains(t, err, "sources.nofluff")
+	assert.ErrorContains(t, err, "sources.justjoin.retries")
+	assert.ErrorContains(t, err, "sources.pracuj.start_url")
+	assert.ErrorContains(t, err, "embedding.url")
+	assert.ErrorContains(t, err, "embedding.model")
+	assert.ErrorContains(t, err, "scrape.rescrape_after")
+	assert.ErrorContains(t, err, "proxies.urls")
+	assert.ErrorContains(t, err, "scrape.captcha.strategy")
+}
+
+func TestRateLimits(t *testing.T) {
+	s := Default().Sources.Nofluff
+	assert.Equal(t, RateLimit{MinDelay: 5 * time.Second, MaxDelay: 10 * time.Second, MaxBackoff: 5 * time.Minute, Burst: 1}, s.ScrapeRateLimit())
+	assert.Equal(t, RateLimit{MinDelay: 3 * time.Second, MaxDelay: 4 * time.Second, MaxBackoff: 5 * time.Minute, Burst: 1}, s.CollectRateLimit())
+
+	s.MaxBackoff = time.Second
+	s.Burst = 0
+	assert.Len(t, s.validate("nofluff", true), 2)
+}
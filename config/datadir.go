@@ -1,8 +0,0 @@
-package config
-
-const (
-	PracujDataDir   = `/home/devpad/.config/google-chrome-canary/profilePracuj`
-	NofluffDataDir  = `/home/devpad/.config/google-chrome-canary/profilenofluff/`
-	JustjoinDataDir = `/home/devpad/.config/google-chrome-canary/profilejustjoin/`
-	BrowserDir = `/usr/bin/google-chrome-canary`
-)
@@ -13,9 +13,9 @@ import (
 const createJobOffer = `-- name: CreateJobOffer :one
 INSERT INTO job_offers (
     id, title, company, location, description, url, source, published_at, skills,
-    salary_employment, salary_b2b, salary_contract
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract
+    salary_employment, salary_b2b, salary_contract, work_mode, country
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id
 `
 
 type CreateJobOfferParams struct {
@@ -31,6 +31,8 @@ type CreateJobOfferParams struct {
 	SalaryEmployment sql.NullString `json:"salary_employment"`
 	SalaryB2b        sql.NullString `json:"salary_b2b"`
 	SalaryContract   sql.NullString `json:"salary_contract"`
+	WorkMode         sql.NullString `json:"work_mode"`
+	Country          sql.NullString `json:"country"`
 }
 
 func (q *Queries) CreateJobOffer(ctx context.Context, arg CreateJobOfferParams) (JobOffer, error) {
@@ -47,6 +49,8 @@ func (q *Queries) CreateJobOffer(ctx context.Context, arg CreateJobOfferParams)
 		arg.SalaryEmployment,
 		arg.SalaryB2b,
 		arg.SalaryContract,
+		arg.WorkMode,
+		arg.Country,
 	)
 	var i JobOffer
 	err := row.Scan(
@@ -64,6 +68,14 @@ func (q *Queries) CreateJobOffer(ctx context.Context, arg CreateJobOfferParams)
 		&i.SalaryEmployment,
 		&i.SalaryB2b,
 		&i.SalaryContract,
+		&i.Embedding,
+		&i.WorkMode,
+		&i.Country,
+		&i.EmbeddingModel,
+		&i.ClosedAt,
+		&i.ExpiresAt,
+		&i.PostingGroupID,
+		&i.CompanyID,
 	)
 	return i, err
 }
@@ -77,9 +89,45 @@ func (q *Queries) DeleteJobOffer(ctx context.Context, id string) error {
 	return err
 }
 
+const getJobOffer = `-- name: GetJobOffer :one
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers
+WHERE id = ?
+`
+
+func (q *Queries) GetJobOffer(ctx context.Context, id string) (JobOffer, error) {
+	row := q.db.QueryRowContext(ctx, getJobOffer, id)
+	var i JobOffer
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Company,
+		&i.Location,
+		&i.Description,
+		&i.Url,
+		&i.Source,
+		&i.PublishedAt,
+		&i.Skills,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+		&i.SalaryEmployment,
+		&i.SalaryB2b,
+		&i.SalaryContract,
+		&i.Embedding,
+		&i.WorkMode,
+		&i.Country,
+		&i.EmbeddingModel,
+		&i.ClosedAt,
+		&i.ExpiresAt,
+		&i.PostingGroupID,
+		&i.CompanyID,
+	)
+	return i, err
+}
+
 const listJobOffers = `-- name: ListJobOffers :many
-SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract FROM job_offers 
-ORDER BY created_at DESC 
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers
+WHERE closed_at IS NULL
+ORDER BY created_at DESC, rowid DESC
 LIMIT ? OFFSET ?
 `
 
@@ -112,6 +160,14 @@ func (q *Queries) ListJobOffers(ctx context.Context, arg ListJobOffersParams) ([
 			&i.SalaryEmployment,
 			&i.SalaryB2b,
 			&i.SalaryContract,
+			&i.Embedding,
+			&i.WorkMode,
+			&i.Country,
+			&i.EmbeddingModel,
+			&i.ClosedAt,
+			&i.ExpiresAt,
+			&i.PostingGroupID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -127,13 +183,13 @@ func (q *Queries) ListJobOffers(ctx context.Context, arg ListJobOffersParams) ([
 }
 
 const listJobOffersByCompany = `-- name: ListJobOffersByCompany :many
-SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract FROM job_offers 
-WHERE company = ?
-ORDER BY published_at DESC
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers 
+WHERE company_id = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
 `
 
-func (q *Queries) ListJobOffersByCompany(ctx context.Context, company sql.NullString) ([]JobOffer, error) {
-	rows, err := q.db.QueryContext(ctx, listJobOffersByCompany, company)
+func (q *Queries) ListJobOffersByCompany(ctx context.Context, companyID sql.NullInt64) ([]JobOffer, error) {
+	rows, err := q.db.QueryContext(ctx, listJobOffersByCompany, companyID)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +212,14 @@ func (q *Queries) ListJobOffersByCompany(ctx context.Context, company sql.NullSt
 			&i.SalaryEmployment,
 			&i.SalaryB2b,
 			&i.SalaryContract,
+			&i.Embedding,
+			&i.WorkMode,
+			&i.Country,
+			&i.EmbeddingModel,
+			&i.ClosedAt,
+			&i.ExpiresAt,
+			&i.PostingGroupID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -170,14 +234,21 @@ func (q *Queries) ListJobOffersByCompany(ctx context.Context, company sql.NullSt
 	return items, nil
 }
 
-const listJobOffersByLocation = `-- name: ListJobOffersByLocation :many
-SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract FROM job_offers 
-WHERE location LIKE ?
-ORDER BY published_at DESC
+const listJobOffersBySource = `-- name: ListJobOffersBySource :many
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers 
+WHERE source = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
+LIMIT ? OFFSET ?
 `
 
-func (q *Queries) ListJobOffersByLocation(ctx context.Context, location sql.NullString) ([]JobOffer, error) {
-	rows, err := q.db.QueryContext(ctx, listJobOffersByLocation, location)
+type ListJobOffersBySourceParams struct {
+	Source string `json:"source"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+func (q *Queries) ListJobOffersBySource(ctx context.Context, arg ListJobOffersBySourceParams) ([]JobOffer, error) {
+	rows, err := q.db.QueryContext(ctx, listJobOffersBySource, arg.Source, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +271,14 @@ func (q *Queries) ListJobOffersByLocation(ctx context.Context, location sql.Null
 			&i.SalaryEmployment,
 			&i.SalaryB2b,
 			&i.SalaryContract,
+			&i.Embedding,
+			&i.WorkMode,
+			&i.Country,
+			&i.EmbeddingModel,
+			&i.ClosedAt,
+			&i.ExpiresAt,
+			&i.PostingGroupID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -214,21 +293,21 @@ func (q *Queries) ListJobOffersByLocation(ctx context.Context, location sql.Null
 	return items, nil
 }
 
-const listJobOffersBySource = `-- name: ListJobOffersBySource :many
-SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract FROM job_offers 
-WHERE source = ?
-ORDER BY published_at DESC 
+const listJobOffersByWorkMode = `-- name: ListJobOffersByWorkMode :many
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers
+WHERE work_mode = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
 LIMIT ? OFFSET ?
 `
 
-type ListJobOffersBySourceParams struct {
-	Source string `json:"source"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type ListJobOffersByWorkModeParams struct {
+	WorkMode sql.NullString `json:"work_mode"`
+	Limit    int64          `json:"limit"`
+	Offset   int64          `json:"offset"`
 }
 
-func (q *Queries) ListJobOffersBySource(ctx context.Context, arg ListJobOffersBySourceParams) ([]JobOffer, error) {
-	rows, err := q.db.QueryContext(ctx, listJobOffersBySource, arg.Source, arg.Limit, arg.Offset)
+func (q *Queries) ListJobOffersByWorkMode(ctx context.Context, arg ListJobOffersByWorkModeParams) ([]JobOffer, error) {
+	rows, err := q.db.QueryContext(ctx, listJobOffersByWorkMode, arg.WorkMode, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -251,6 +330,14 @@ func (q *Queries) ListJobOffersBySource(ctx context.Context, arg ListJobOffersBy
 			&i.SalaryEmployment,
 			&i.SalaryB2b,
 			&i.SalaryContract,
+			&i.Embedding,
+			&i.WorkMode,
+			&i.Country,
+			&i.EmbeddingModel,
+			&i.ClosedAt,
+			&i.ExpiresAt,
+			&i.PostingGroupID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -266,8 +353,9 @@ func (q *Queries) ListJobOffersBySource(ctx context.Context, arg ListJobOffersBy
 }
 
 const listRecentJobOffers = `-- name: ListRecentJobOffers :many
-SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract FROM job_offers 
-ORDER BY published_at DESC 
+SELECT id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id FROM job_offers
+WHERE closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
 LIMIT ?
 `
 
@@ -295,6 +383,14 @@ func (q *Queries) ListRecentJobOffers(ctx context.Context, limit int64) ([]JobOf
 			&i.SalaryEmployment,
 			&i.SalaryB2b,
 			&i.SalaryContract,
+			&i.Embedding,
+			&i.WorkMode,
+			&i.Country,
+			&i.EmbeddingModel,
+			&i.ClosedAt,
+			&i.ExpiresAt,
+			&i.PostingGroupID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -321,9 +417,11 @@ SET
     salary_employment = ?,
     salary_b2b = ?,
     salary_contract = ?,
+    work_mode = ?,
+    country = ?,
     last_seen_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract
+RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id
 `
 
 type UpdateJobOfferParams struct {
@@ -336,6 +434,8 @@ type UpdateJobOfferParams struct {
 	SalaryEmployment sql.NullString `json:"salary_employment"`
 	SalaryB2b        sql.NullString `json:"salary_b2b"`
 	SalaryContract   sql.NullString `json:"salary_contract"`
+	WorkMode         sql.NullString `json:"work_mode"`
+	Country          sql.NullString `json:"country"`
 	ID               string         `json:"id"`
 }
 
@@ -350,6 +450,8 @@ func (q *Queries) UpdateJobOffer(ctx context.Context, arg UpdateJobOfferParams)
 		arg.SalaryEmployment,
 		arg.SalaryB2b,
 		arg.SalaryContract,
+		arg.WorkMode,
+		arg.Country,
 		arg.ID,
 	)
 	var i JobOffer
@@ -368,6 +470,14 @@ func (q *Queries) UpdateJobOffer(ctx context.Context, arg UpdateJobOfferParams)
 		&i.SalaryEmployment,
 		&i.SalaryB2b,
 		&i.SalaryContract,
+		&i.Embedding,
+		&i.WorkMode,
+		&i.Country,
+		&i.EmbeddingModel,
+		&i.ClosedAt,
+		&i.ExpiresAt,
+		&i.PostingGroupID,
+		&i.CompanyID,
 	)
 	return i, err
 }
@@ -375,20 +485,24 @@ func (q *Queries) UpdateJobOffer(ctx context.Context, arg UpdateJobOfferParams)
 const upsertJobOffer = `-- name: UpsertJobOffer :one
 INSERT INTO job_offers (
     id, title, company, location, description, url, source, published_at, skills,
-    salary_employment, salary_b2b, salary_contract
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    salary_employment, salary_b2b, salary_contract, work_mode, country, expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(url) DO UPDATE SET
     title = excluded.title,
     company = excluded.company,
     location = excluded.location,
     description = excluded.description,
-    published_at = excluded.published_at,
+    published_at = COALESCE(excluded.published_at, job_offers.published_at),
     skills = excluded.skills,
     salary_employment = excluded.salary_employment,
     salary_b2b = excluded.salary_b2b,
     salary_contract = excluded.salary_contract,
+    work_mode = excluded.work_mode,
+    country = excluded.country,
+    expires_at = COALESCE(excluded.expires_at, job_offers.expires_at),
+    closed_at = NULL,
     last_seen_at = CURRENT_TIMESTAMP
-RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract
+RETURNING id, title, company, location, description, url, source, published_at, skills, created_at, last_seen_at, salary_employment, salary_b2b, salary_contract, embedding, work_mode, country, embedding_model, closed_at, expires_at, posting_group_id, company_id
 `
 
 type UpsertJobOfferParams struct {
@@ -404,8 +518,12 @@ type UpsertJobOfferParams struct {
 	SalaryEmployment sql.NullString `json:"salary_employment"`
 	SalaryB2b        sql.NullString `json:"salary_b2b"`
 	SalaryContract   sql.NullString `json:"salary_contract"`
+	WorkMode         sql.NullString `json:"work_mode"`
+	Country          sql.NullString `json:"country"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
 }
 
+// a rescrape without dates keeps the ones found before
 func (q *Queries) UpsertJobOffer(ctx context.Context, arg UpsertJobOfferParams) (JobOffer, error) {
 	row := q.db.QueryRowContext(ctx, upsertJobOffer,
 		arg.ID,
@@ -420,6 +538,9 @@ func (q *Queries) UpsertJobOffer(ctx context.Context, arg UpsertJobOfferParams)
 		arg.SalaryEmployment,
 		arg.SalaryB2b,
 		arg.SalaryContract,
+		arg.WorkMode,
+		arg.Country,
+		arg.ExpiresAt,
 	)
 	var i JobOffer
 	err := row.Scan(
@@ -437,6 +558,14 @@ func (q *Queries) UpsertJobOffer(ctx context.Context, arg UpsertJobOfferParams)
 		&i.SalaryEmployment,
 		&i.SalaryB2b,
 		&i.SalaryContract,
+		&i.Embedding,
+		&i.WorkMode,
+		&i.Country,
+		&i.EmbeddingModel,
+		&i.ClosedAt,
+		&i.ExpiresAt,
+		&i.PostingGroupID,
+		&i.CompanyID,
 	)
 	return i, err
 }
@@ -6,8 +6,46 @@ package database
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Company struct {
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	NormalizedName string       `json:"normalized_name"`
+	CreatedAt      sql.NullTime `json:"created_at"`
+}
+
+type CompanyAlias struct {
+	Alias     string `json:"alias"`
+	CompanyID int64  `json:"company_id"`
+}
+
+type CompanyProfile struct {
+	CompanyID int64  `json:"company_id"`
+	Source    string `json:"source"`
+	Url       string `json:"url"`
+}
+
+type DiscoveredUrl struct {
+	Url               string       `json:"url"`
+	Source            string       `json:"source"`
+	FirstDiscoveredAt time.Time    `json:"first_discovered_at"`
+	LastDiscoveredAt  time.Time    `json:"last_discovered_at"`
+	Status            string       `json:"status"`
+	ScrapedAt         sql.NullTime `json:"scraped_at"`
+}
+
+type FailedUrl struct {
+	Url           string    `json:"url"`
+	Source        string    `json:"source"`
+	Error         string    `json:"error"`
+	Permanent     bool      `json:"permanent"`
+	Attempts      int64     `json:"attempts"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
 type JobOffer struct {
 	ID               string         `json:"id"`
 	Title            string         `json:"title"`
@@ -23,4 +61,82 @@ type JobOffer struct {
 	SalaryEmployment sql.NullString `json:"salary_employment"`
 	SalaryB2b        sql.NullString `json:"salary_b2b"`
 	SalaryContract   sql.NullString `json:"salary_contract"`
+	Embedding        []byte         `json:"embedding"`
+	WorkMode         sql.NullString `json:"work_mode"`
+	Country          sql.NullString `json:"country"`
+	EmbeddingModel   sql.NullString `json:"embedding_model"`
+	ClosedAt         sql.NullTime   `json:"closed_at"`
+	ExpiresAt        sql.NullTime   `json:"expires_at"`
+	PostingGroupID   sql.NullString `json:"posting_group_id"`
+	CompanyID        sql.NullInt64  `json:"company_id"`
+}
+
+type JobOfferCity struct {
+	JobOfferID string `json:"job_offer_id"`
+	City       string `json:"city"`
+}
+
+type JobOfferSalary struct {
+	JobOfferID   string         `json:"job_offer_id"`
+	ContractType string         `json:"contract_type"`
+	MinAmount    float64        `json:"min_amount"`
+	MaxAmount    float64        `json:"max_amount"`
+	Currency     sql.NullString `json:"currency"`
+	Period       string         `json:"period"`
+	Gross        sql.NullBool   `json:"gross"`
+	MonthlyMin   float64        `json:"monthly_min"`
+	MonthlyMax   float64        `json:"monthly_max"`
+	Raw          string         `json:"raw"`
+}
+
+type JobOfferVersion struct {
+	JobOfferID string         `json:"job_offer_id"`
+	Version    int64          `json:"version"`
+	Field      string         `json:"field"`
+	OldValue   sql.NullString `json:"old_value"`
+	NewValue   sql.NullString `json:"new_value"`
+	ChangedAt  time.Time      `json:"changed_at"`
+}
+
+type RunHistory struct {
+	ID         int64          `json:"id"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	DurationMs int64          `json:"duration_ms"`
+	Error      sql.NullString `json:"error"`
+}
+
+type RunHistorySource struct {
+	RunID       int64  `json:"run_id"`
+	Source      string `json:"source"`
+	Attempted   int64  `json:"attempted"`
+	Saved       int64  `json:"saved"`
+	Closed      int64  `json:"closed"`
+	FetchFailed int64  `json:"fetch_failed"`
+	ParseFailed int64  `json:"parse_failed"`
+	SaveFailed  int64  `json:"save_failed"`
+	Captchas    int64  `json:"captchas"`
+}
+
+type ScrapeRun struct {
+	ID         int64        `json:"id"`
+	Kind       string       `json:"kind"`
+	Source     string       `json:"source"`
+	RunKey     string       `json:"run_key"`
+	Page       int64        `json:"page"`
+	StartedAt  time.Time    `json:"started_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	FinishedAt sql.NullTime `json:"finished_at"`
+}
+
+type ScrapeRunUrl struct {
+	RunID    int64        `json:"run_id"`
+	Position int64        `json:"position"`
+	Url      string       `json:"url"`
+	DoneAt   sql.NullTime `json:"done_at"`
+}
+
+type SearchIndexState struct {
+	ID    int64 `json:"id"`
+	Stale bool  `json:"stale"`
 }
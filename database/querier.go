@@ -10,15 +10,97 @@ import (
 )
 
 type Querier interface {
+	ClearJobOffersSearch(ctx context.Context) error
+	CloseJobOffer(ctx context.Context, id string) error
+	CloseJobOfferByUrl(ctx context.Context, url string) (int64, error)
+	CountCompanyOffers(ctx context.Context, companyID sql.NullInt64) (CountCompanyOffersRow, error)
+	CountCompanySkills(ctx context.Context, arg CountCompanySkillsParams) ([]CountCompanySkillsRow, error)
+	CountFilteredJobOffers(ctx context.Context, arg CountFilteredJobOffersParams) (int64, error)
+	CountJobOffersByCity(ctx context.Context, limit int64) ([]CountJobOffersByCityRow, error)
+	CountJobOffersBySkill(ctx context.Context, limit int64) ([]CountJobOffersBySkillRow, error)
+	CountJobOffersBySource(ctx context.Context) ([]CountJobOffersBySourceRow, error)
 	CreateJobOffer(ctx context.Context, arg CreateJobOfferParams) (JobOffer, error)
+	CreateScrapeRun(ctx context.Context, arg CreateScrapeRunParams) (ScrapeRun, error)
+	DeleteFailedUrl(ctx context.Context, url string) error
 	DeleteJobOffer(ctx context.Context, id string) error
+	DeleteJobOfferCities(ctx context.Context, jobOfferID string) error
+	DeleteJobOfferSalaries(ctx context.Context, jobOfferID string) error
+	DeleteJobOfferSearch(ctx context.Context, jobOfferID string) error
+	DeleteOpenScrapeRuns(ctx context.Context, arg DeleteOpenScrapeRunsParams) error
+	DeleteScrapeRunUrls(ctx context.Context, runID int64) error
+	FindCompanies(ctx context.Context, arg FindCompaniesParams) ([]Company, error)
+	// every filter is optional, NULL leaves it out, one_per_group keeps one matching offer per job
+	FilterJobOffers(ctx context.Context, arg FilterJobOffersParams) ([]JobOffer, error)
+	FinishScrapeRun(ctx context.Context, id int64) error
+	GetCompanyByAlias(ctx context.Context, alias string) (Company, error)
+	GetCompanyByNormalizedName(ctx context.Context, normalizedName string) (Company, error)
+	GetFailedUrl(ctx context.Context, url string) (FailedUrl, error)
+	GetJobOffer(ctx context.Context, id string) (JobOffer, error)
+	GetJobOfferByUrl(ctx context.Context, url string) (JobOffer, error)
+	GetLatestJobOfferVersion(ctx context.Context, jobOfferID string) (int64, error)
+	GetOpenScrapeRun(ctx context.Context, arg GetOpenScrapeRunParams) (ScrapeRun, error)
+	GetSearchIndexStale(ctx context.Context) (bool, error)
+	InsertCompanyAlias(ctx context.Context, arg InsertCompanyAliasParams) error
+	InsertJobOfferCity(ctx context.Context, arg InsertJobOfferCityParams) error
+	InsertJobOfferSearch(ctx context.Context, arg InsertJobOfferSearchParams) error
+	InsertJobOfferVersion(ctx context.Context, arg InsertJobOfferVersionParams) error
+	InsertRunHistory(ctx context.Context, arg InsertRunHistoryParams) (RunHistory, error)
+	InsertRunHistorySource(ctx context.Context, arg InsertRunHistorySourceParams) error
+	InsertScrapeRunUrl(ctx context.Context, arg InsertScrapeRunUrlParams) error
+	ListCompanyAliases(ctx context.Context, companyID int64) ([]string, error)
+	ListCompanyProfiles(ctx context.Context, companyID int64) ([]ListCompanyProfilesRow, error)
+	// one offer per job, so a job posted on three boards counts once towards the median
+	ListCompanySalaries(ctx context.Context, companyID sql.NullInt64) ([]ListCompanySalariesRow, error)
+	ListDiscoveredUrls(ctx context.Context, source string) ([]DiscoveredUrl, error)
+	ListFailedUrls(ctx context.Context, source sql.NullString) ([]FailedUrl, error)
+	ListJobOfferCities(ctx context.Context, jobOfferID string) ([]string, error)
+	ListJobOfferEmbeddings(ctx context.Context, embeddingModel sql.NullString) ([]ListJobOfferEmbeddingsRow, error)
+	ListJobOfferSalaries(ctx context.Context, jobOfferID string) ([]JobOfferSalary, error)
+	ListJobOfferStatesBySource(ctx context.Context, source string) ([]ListJobOfferStatesBySourceRow, error)
+	ListJobOfferVersions(ctx context.Context, jobOfferID string) ([]JobOfferVersion, error)
 	ListJobOffers(ctx context.Context, arg ListJobOffersParams) ([]JobOffer, error)
-	ListJobOffersByCompany(ctx context.Context, company sql.NullString) ([]JobOffer, error)
-	ListJobOffersByLocation(ctx context.Context, location sql.NullString) ([]JobOffer, error)
+	ListJobOffersByCity(ctx context.Context, arg ListJobOffersByCityParams) ([]JobOffer, error)
+	ListJobOffersByCityAndWorkMode(ctx context.Context, arg ListJobOffersByCityAndWorkModeParams) ([]JobOffer, error)
+	ListJobOffersByCompany(ctx context.Context, companyID sql.NullInt64) ([]JobOffer, error)
+	ListJobOffersByMonthlySalary(ctx context.Context, arg ListJobOffersByMonthlySalaryParams) ([]JobOffer, error)
 	ListJobOffersBySource(ctx context.Context, arg ListJobOffersBySourceParams) ([]JobOffer, error)
+	ListJobOffersByWorkMode(ctx context.Context, arg ListJobOffersByWorkModeParams) ([]JobOffer, error)
+	ListJobOffersForSearch(ctx context.Context) ([]ListJobOffersForSearchRow, error)
+	ListJobOffersWithoutEmbedding(ctx context.Context, arg ListJobOffersWithoutEmbeddingParams) ([]JobOffer, error)
+	// offers stored before companies existed
+	ListJobOffersWithoutCompany(ctx context.Context) ([]ListJobOffersWithoutCompanyRow, error)
+	ListPostingCandidateCities(ctx context.Context) ([]JobOfferCity, error)
+	// what dedupe compares in insert order, so the first offer of a job becomes its canonical one
+	ListPostingCandidates(ctx context.Context) ([]ListPostingCandidatesRow, error)
+	ListPostingGroupOffers(ctx context.Context, postingGroupID sql.NullString) ([]ListPostingGroupOffersRow, error)
 	ListRecentJobOffers(ctx context.Context, limit int64) ([]JobOffer, error)
+	ListRunHistory(ctx context.Context, limit int64) ([]RunHistory, error)
+	ListRunHistorySources(ctx context.Context, runID int64) ([]RunHistorySource, error)
+	ListScrapeRunUrls(ctx context.Context, runID int64) ([]ScrapeRunUrl, error)
+	MarkScrapeRunUrlDone(ctx context.Context, arg MarkScrapeRunUrlDoneParams) error
+	// only an existing index goes stale, a database without one gets it built by the fts5 migration
+	MarkSearchIndexStale(ctx context.Context) (int64, error)
+	// last_seen_at stays, it tells when the offer page was last scraped
+	ReopenJobOffer(ctx context.Context, id string) error
+	SalaryStatsByContract(ctx context.Context) ([]SalaryStatsByContractRow, error)
+	// bm25 weights follow the fts columns: job_offer_id, title, company, description, skills
+	SearchJobOffers(ctx context.Context, arg SearchJobOffersParams) ([]SearchJobOffersRow, error)
+	SetDiscoveredUrlStatus(ctx context.Context, arg SetDiscoveredUrlStatusParams) error
+	SetJobOfferCompany(ctx context.Context, arg SetJobOfferCompanyParams) error
+	SetJobOfferPostingGroup(ctx context.Context, arg SetJobOfferPostingGroupParams) error
+	SetSearchIndexStale(ctx context.Context, stale bool) error
 	UpdateJobOffer(ctx context.Context, arg UpdateJobOfferParams) (JobOffer, error)
+	UpdateJobOfferEmbedding(ctx context.Context, arg UpdateJobOfferEmbeddingParams) error
+	UpdateScrapeRunPage(ctx context.Context, arg UpdateScrapeRunPageParams) error
+	// the first spelling seen stays the display name
+	UpsertCompany(ctx context.Context, arg UpsertCompanyParams) (Company, error)
+	UpsertCompanyProfile(ctx context.Context, arg UpsertCompanyProfileParams) error
+	// a url listed again after it closed waits for a scrape again
+	UpsertDiscoveredUrl(ctx context.Context, arg UpsertDiscoveredUrlParams) error
+	UpsertFailedUrl(ctx context.Context, arg UpsertFailedUrlParams) error
+	// a rescrape without dates keeps the ones found before
 	UpsertJobOffer(ctx context.Context, arg UpsertJobOfferParams) (JobOffer, error)
+	UpsertJobOfferSalary(ctx context.Context, arg UpsertJobOfferSalaryParams) error
 }
 
 var _ Querier = (*Queries)(nil)
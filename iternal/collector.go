@@ -4,36 +4,193 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/embedding"
 	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
 	"log"
 	"time"
 )
 
-func StartCollector(ctx context.Context, db *sql.DB, scrapers []scraper.Scraper,parallel bool) {
-	out := scraper.RunScrapers(ctx, scrapers,parallel)
-	querier := database.New(db)
+// StartCollector runs the scrapers and upserts every offer they produce with canonical skill names,
+// embedding them when emb is set. Offers the scrapers found expired are closed instead,
+// urls they failed on are filed for retry-failed and leave the queue once they get through.
+// The scrape status of every discovered url is kept up to date.
+// Every handled url, a filed failure included, is marked done in the scrape run of its source
+// and counted in stats, runs and stats may be nil. Returns scraper errors and failed saves joined together.
+func StartCollector(ctx context.Context, db *sql.DB, scrapers []scraper.Scraper, parallel bool, norm *skills.Normalizer, emb embedding.Embedder, runs Runs, stats *scraper.Stats) error {
+	out, scraperErrs := scraper.RunScrapers(ctx, scrapers, parallel)
+	saved, closed, failed := 0, 0, 0
 
 	for job := range out {
+		if job.Err != nil {
+			// a filed url is retry-failed's to finish, leaving it pending would keep the run
+			// open and every later scrape resuming only the urls that keep failing
+			if err := recordFailure(ctx, db, job); err != nil {
+				log.Printf("Error %s in filing failed url: %s", err, job.URL)
+			} else if !job.Expired {
+				runs.done(ctx, job)
+			}
+			if !job.Expired {
+				setURLStatus(ctx, db, job.URL, URLFailed)
+				continue
+			}
+		}
+		if job.Expired {
+			ok, err := closeExpiredOffer(ctx, db, job.URL)
+			if err != nil {
+				log.Printf("Error %s in closing expired offer: %s", err, job.URL)
+				stats.Count(job.Source, scraper.SaveFailed)
+				failed++
+				continue
+			}
+			if ok {
+				log.Printf("Closed expired offer: %s", job.URL)
+				stats.Count(job.Source, scraper.Closed)
+				closed++
+			}
+			if job.Err == nil {
+				clearFailure(ctx, db, job.URL)
+			}
+			setURLStatus(ctx, db, job.URL, URLClosed)
+			runs.done(ctx, job)
+			continue
+		}
+		job.Skills = norm.Normalize(job.Skills)
 		log.Printf("Saving job: %s from %s", job.Title, job.Company)
-		skillsJSON, _ := json.Marshal(job.Skills)
-		params := database.UpsertJobOfferParams{
-			ID:               uuid.New().String(),
-			Title:            job.Title,
-			Company:          sql.NullString{String: job.Company, Valid: job.Company != ""},
-			Location:         sql.NullString{String: job.Location, Valid: job.Location != ""},
-			Description:      sql.NullString{String: job.Description, Valid: job.Description != ""},
-			Url:              urlNormalizer(job.URL),
-			Source:           job.Source,
-			PublishedAt:      sql.NullTime{Time: time.Now(), Valid: true},
-			Skills:           sql.NullString{String: string(skillsJSON), Valid: len(job.Skills) > 0},
-			SalaryEmployment: sql.NullString{String: job.SalaryEmployment, Valid: job.SalaryEmployment != ""},
-			SalaryB2b:        sql.NullString{String: job.SalaryB2B, Valid: job.SalaryB2B != ""},
-			SalaryContract:   sql.NullString{String: job.SalaryContract, Valid: job.SalaryContract != ""},
-		}
-		if _, err := querier.UpsertJobOffer(ctx, params); err != nil {
-			log.Printf("Error %s in saving: %s from %s",err, job.Title, job.Company)
+		if err := saveJobOffer(ctx, db, job, emb); err != nil {
+			log.Printf("Error %s in saving: %s from %s", err, job.Title, job.Company)
+			stats.Count(job.Source, scraper.SaveFailed)
+			failed++
+			continue
+		}
+		stats.Count(job.Source, scraper.Saved)
+		saved++
+		clearFailure(ctx, db, job.URL)
+		setURLStatus(ctx, db, job.URL, URLScraped)
+		runs.done(ctx, job)
+	}
+
+	var errs []error
+	for err := range scraperErrs {
+		errs = append(errs, err)
+	}
+	if failed > 0 {
+		errs = append(errs, fmt.Errorf("failed to save %d of %d offers", failed, saved+closed+failed))
+	}
+	log.Printf("Saved %d offers, closed %d expired", saved, closed)
+	return errors.Join(errs...)
+}
+
+// upserts the offer and everything derived from it in one transaction,
+// a failed embedding is only logged, the offer is saved without a vector
+func saveJobOffer(ctx context.Context, db *sql.DB, job scraper.JobOffer, emb embedding.Embedder) error {
+	// embed before the transaction, http embedders can be slow
+	var vector []float32
+	if emb != nil {
+		vectors, err := emb.Embed(ctx, []string{embeddingText(job)})
+		if err != nil {
+			log.Printf("Error %s in embedding: %s from %s", err, job.Title, job.Company)
+		} else {
+			vector = vectors[0]
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	querier := database.New(db).WithTx(tx)
+
+	skillsJSON, _ := json.Marshal(job.Skills)
+	params := database.UpsertJobOfferParams{
+		ID:               uuid.New().String(),
+		Title:            job.Title,
+		Company:          sql.NullString{String: job.Company, Valid: job.Company != ""},
+		Location:         sql.NullString{String: job.Location, Valid: job.Location != ""},
+		Description:      sql.NullString{String: job.Description, Valid: job.Description != ""},
+		Url:              urlNormalizer(job.URL),
+		Source:           job.Source,
+		PublishedAt:      nullTime(job.PublishedAt),
+		Skills:           sql.NullString{String: string(skillsJSON), Valid: len(job.Skills) > 0},
+		SalaryEmployment: sql.NullString{String: job.SalaryEmployment, Valid: job.SalaryEmployment != ""},
+		SalaryB2b:        sql.NullString{String: job.SalaryB2B, Valid: job.SalaryB2B != ""},
+		SalaryContract:   sql.NullString{String: job.SalaryContract, Valid: job.SalaryContract != ""},
+		WorkMode:         sql.NullString{String: string(job.Place.WorkMode), Valid: job.Place.WorkMode != ""},
+		Country:          sql.NullString{String: job.Place.Country, Valid: job.Place.Country != ""},
+		ExpiresAt:        nullTime(job.ExpiresAt),
+	}
+	stored, err := querier.GetJobOfferByUrl(ctx, params.Url)
+	existed := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	offer, err := querier.UpsertJobOffer(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if existed {
+		if changes := diffJobOffer(stored, params); len(changes) > 0 {
+			if err := saveVersion(ctx, querier, offer.ID, changes); err != nil {
+				return fmt.Errorf("saving version: %w", err)
+			}
 		}
 	}
+
+	companyID, err := saveCompany(ctx, querier, job.Company, job.Source, job.CompanyURL)
+	if err != nil {
+		return fmt.Errorf("saving company: %w", err)
+	}
+	if err := querier.SetJobOfferCompany(ctx, database.SetJobOfferCompanyParams{CompanyID: companyID, ID: offer.ID}); err != nil {
+		return fmt.Errorf("saving company: %w", err)
+	}
+	if err := saveSalaries(ctx, querier, offer.ID, job); err != nil {
+		return fmt.Errorf("saving salaries: %w", err)
+	}
+	if err := saveCities(ctx, querier, offer.ID, job.Place.Cities); err != nil {
+		return fmt.Errorf("saving cities: %w", err)
+	}
+	if err := indexJobOffer(ctx, querier, offer.ID, job); err != nil {
+		return fmt.Errorf("indexing for search: %w", err)
+	}
+	if vector != nil {
+		params := database.UpdateJobOfferEmbeddingParams{
+			Embedding:      embedding.Encode(vector),
+			EmbeddingModel: sql.NullString{String: emb.Model(), Valid: true},
+			ID:             offer.ID,
+		}
+		if err := querier.UpdateJobOfferEmbedding(ctx, params); err != nil {
+			return fmt.Errorf("saving embedding: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// replaces the cities of an offer, rescraped offers can move or drop locations
+func saveCities(ctx context.Context, q *database.Queries, offerID string, cities []string) error {
+	if err := q.DeleteJobOfferCities(ctx, offerID); err != nil {
+		return err
+	}
+	for _, city := range cities {
+		params := database.InsertJobOfferCityParams{JobOfferID: offerID, City: city}
+		if err := q.InsertJobOfferCity(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// a date the page did not show stays NULL, the upsert keeps the stored one
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
 }
@@ -0,0 +1,132 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveJobOfferStoresStructuredSalaries(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	job := scraper.JobOffer{
+		Title:            "Senior Go Developer",
+		URL:              "https://www.pracuj.pl/praca/senior-go,oferta,1?s=abc",
+		Source:           "pracuj.pl",
+		SalaryEmployment: "18 000–24 000 zł brutto / mies. (umowa o pracę)",
+		SalaryB2B:        "150–190 zł netto (+ VAT) / godz. (kontrakt B2B)",
+	}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+
+	offers, err := q.ListJobOffers(ctx, database.ListJobOffersParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, job.SalaryB2B, offers[0].SalaryB2b.String, "raw text is kept")
+
+	salaries, err := q.ListJobOfferSalaries(ctx, offers[0].ID)
+	require.NoError(t, err)
+	require.Len(t, salaries, 2)
+	assert.Equal(t, "b2b", salaries[0].ContractType)
+	assert.Equal(t, "hour", salaries[0].Period)
+	assert.Equal(t, 190.0*168, salaries[0].MonthlyMax)
+	assert.False(t, salaries[0].Gross.Bool)
+	assert.Equal(t, "employment", salaries[1].ContractType)
+	assert.Equal(t, 18000.0, salaries[1].MinAmount)
+	assert.True(t, salaries[1].Gross.Bool)
+
+	// rescrape without b2b drops the stale row
+	job.SalaryB2B = ""
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	salaries, err = q.ListJobOfferSalaries(ctx, offers[0].ID)
+	require.NoError(t, err)
+	require.Len(t, salaries, 1)
+
+	byPay, err := q.ListJobOffersByMonthlySalary(ctx, database.ListJobOffersByMonthlySalaryParams{
+		Currency:   salaries[0].Currency,
+		MonthlyMax: 20000,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, byPay, 1)
+}
+
+func TestSaveJobOfferStoresLocation(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	job := scraper.JobOffer{
+		Title:    "Backend Engineer",
+		URL:      "https://nofluffjobs.com/pl/job/backend-engineer",
+		Source:   "nofluffjobs.com",
+		Location: "Warszawa, Kraków (hybrid)",
+		Place: scraper.Location{
+			Cities:   []string{"Warszawa", "Kraków"},
+			Country:  "PL",
+			WorkMode: scraper.WorkModeHybrid,
+		},
+	}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+
+	offers, err := q.ListJobOffersByCityAndWorkMode(ctx, database.ListJobOffersByCityAndWorkModeParams{
+		City:     "Kraków",
+		WorkMode: sql.NullString{String: "hybrid", Valid: true},
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "PL", offers[0].Country.String)
+	assert.Equal(t, job.Location, offers[0].Location.String, "raw text is kept")
+
+	// rescrape with a single city drops the other one
+	job.Place.Cities = []string{"Warszawa"}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	cities, err := q.ListJobOfferCities(ctx, offers[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Warszawa"}, cities)
+}
+
+func TestSaveJobOfferKeepsScrapedDates(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	published := time.Date(2025, time.October, 12, 0, 0, 0, 0, time.UTC)
+	expires := time.Date(2025, time.November, 11, 0, 0, 0, 0, time.UTC)
+	job := scraper.JobOffer{
+		Title:       "Senior Go Developer",
+		URL:         "https://www.pracuj.pl/praca/senior-go,oferta,1",
+		Source:      "pracuj.pl",
+		PublishedAt: &published,
+		ExpiresAt:   &expires,
+	}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+
+	// a rescrape that found no dates doesn't republish the offer
+	job.PublishedAt, job.ExpiresAt = nil, nil
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	stored, err := q.GetJobOfferByUrl(ctx, job.URL)
+	require.NoError(t, err)
+	assert.True(t, published.Equal(stored.PublishedAt.Time))
+	assert.True(t, expires.Equal(stored.ExpiresAt.Time))
+
+	// an offer without any date sorts and exports by when it was first scraped
+	undated := scraper.JobOffer{Title: "QA Tester", URL: "https://nofluffjobs.com/pl/job/qa", Source: "nofluffjobs.com"}
+	require.NoError(t, saveJobOffer(ctx, db, undated, nil))
+	recent, err := q.ListRecentJobOffers(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, undated.URL, recent[0].Url)
+	assert.False(t, recent[0].PublishedAt.Valid)
+	exported := jobOfferFromRow(recent[0])
+	require.NotNil(t, exported.PublishedAt)
+	assert.True(t, recent[0].CreatedAt.Time.Equal(*exported.PublishedAt))
+}
@@ -0,0 +1,255 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// how many companies a partial name may match before FindCompany gives up listing them
+const companyMatchLimit = 10
+
+// legal forms and country suffixes written after a company name, in lowercase words,
+// "sp. z o.o." is the words sp z o o
+var legalForms = [][]string{
+	{"sp", "z", "o", "o"}, {"sp", "z", "oo"}, {"spółka", "z", "ograniczoną", "odpowiedzialnością"},
+	{"s", "a"}, {"sa"}, {"spółka", "akcyjna"},
+	{"sp", "k"}, {"spk"}, {"spółka", "komandytowa"}, {"sp", "j"}, {"spj"}, {"spółka", "jawna"},
+	{"s", "k", "a"}, {"ska"}, {"spółka", "komandytowo", "akcyjna"},
+	{"gmbh"}, {"ltd"}, {"limited"}, {"inc"}, {"llc"}, {"plc"}, {"b", "v"}, {"bv"}, {"ag"},
+	{"polska"}, {"poland"},
+}
+
+// company name without the legal forms it ends with, "ACME Sp. z o.o." and "ACME Polska" are both "acme".
+// A name that is nothing but a legal form is kept whole, only a name without words has no key.
+func normalizeCompany(company string) string {
+	kept := words(company)
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, form := range legalForms {
+			if len(kept) > len(form) && slices.Equal(kept[len(kept)-len(form):], form) {
+				kept = kept[:len(kept)-len(form)]
+				trimmed = true
+				break
+			}
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// CompanySalary is the median monthly salary of a company's open jobs on one contract
+type CompanySalary struct {
+	Contract         string  `json:"contract"`
+	Currency         string  `json:"currency"`
+	Offers           int     `json:"offers"`
+	MedianMonthlyMin float64 `json:"median_monthly_min"`
+	MedianMonthlyMax float64 `json:"median_monthly_max"`
+}
+
+// SkillCount is a skill and the number of open jobs asking for it
+type SkillCount struct {
+	Skill  string `json:"skill"`
+	Offers int64  `json:"offers"`
+}
+
+// Company is a company with every spelling and board page seen for it and stats of its open offers
+type Company struct {
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Aliases  []string          `json:"aliases"`
+	Profiles map[string]string `json:"profiles,omitempty"` // source -> company page
+	// open offers, and jobs counting an offer posted on several boards once
+	OpenOffers int64              `json:"open_offers"`
+	Jobs       int64              `json:"jobs"`
+	Salaries   []CompanySalary    `json:"salaries"`
+	TopSkills  []SkillCount       `json:"top_skills"`
+	Offers     []scraper.JobOffer `json:"offers"`
+}
+
+// saveCompany links a scraped company name to its companies row, creating it on first sight,
+// and records the board's company page. A name without any words is no company.
+func saveCompany(ctx context.Context, q *database.Queries, name, source, profileURL string) (sql.NullInt64, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	normalized := normalizeCompany(name)
+	if normalized == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	company, err := q.GetCompanyByAlias(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		company, err = q.UpsertCompany(ctx, database.UpsertCompanyParams{Name: name, NormalizedName: normalized})
+		if err != nil {
+			return sql.NullInt64{}, err
+		}
+		err = q.InsertCompanyAlias(ctx, database.InsertCompanyAliasParams{Alias: name, CompanyID: company.ID})
+	}
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	if profileURL != "" {
+		params := database.UpsertCompanyProfileParams{CompanyID: company.ID, Source: source, Url: profileURL}
+		if err := q.UpsertCompanyProfile(ctx, params); err != nil {
+			return sql.NullInt64{}, err
+		}
+	}
+	return sql.NullInt64{Int64: company.ID, Valid: true}, nil
+}
+
+// LinkCompanies links offers stored before companies existed to their company, returns how many
+func LinkCompanies(ctx context.Context, db *sql.DB) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	querier := database.New(db).WithTx(tx)
+
+	rows, err := querier.ListJobOffersWithoutCompany(ctx)
+	if err != nil {
+		return 0, err
+	}
+	linked := 0
+	for _, row := range rows {
+		companyID, err := saveCompany(ctx, querier, row.Company.String, row.Source, "")
+		if err != nil {
+			return 0, err
+		}
+		if !companyID.Valid {
+			continue
+		}
+		if err := querier.SetJobOfferCompany(ctx, database.SetJobOfferCompanyParams{CompanyID: companyID, ID: row.ID}); err != nil {
+			return 0, err
+		}
+		linked++
+	}
+	return linked, tx.Commit()
+}
+
+// FindCompany finds a company by any spelling seen for it, a name matching no company exactly
+// may be part of exactly one company's name
+func FindCompany(ctx context.Context, db *sql.DB, name string) (database.Company, error) {
+	q := database.New(db)
+	company, err := q.GetCompanyByAlias(ctx, strings.Join(strings.Fields(name), " "))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return company, err
+	}
+	normalized := normalizeCompany(name)
+	if normalized == "" {
+		return database.Company{}, fmt.Errorf("no company named %q", name)
+	}
+	company, err = q.GetCompanyByNormalizedName(ctx, normalized)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return company, err
+	}
+
+	matches, err := q.FindCompanies(ctx, database.FindCompaniesParams{NormalizedName: normalized, Limit: companyMatchLimit})
+	if err != nil {
+		return database.Company{}, err
+	}
+	switch len(matches) {
+	case 0:
+		return database.Company{}, fmt.Errorf("no company named %q", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.Name
+	}
+	return database.Company{}, fmt.Errorf("%q matches several companies: %s", name, strings.Join(names, ", "))
+}
+
+// CompanySummary returns the company name refers to with its open offers newest first,
+// the median salaries of its jobs and the topSkills skills they ask for most
+func CompanySummary(ctx context.Context, db *sql.DB, name string, topSkills int) (Company, error) {
+	row, err := FindCompany(ctx, db, name)
+	if err != nil {
+		return Company{}, err
+	}
+	q := database.New(db)
+	companyID := sql.NullInt64{Int64: row.ID, Valid: true}
+	company := Company{ID: row.ID, Name: row.Name}
+
+	if company.Aliases, err = q.ListCompanyAliases(ctx, row.ID); err != nil {
+		return Company{}, err
+	}
+	profiles, err := q.ListCompanyProfiles(ctx, row.ID)
+	if err != nil {
+		return Company{}, err
+	}
+	for _, p := range profiles {
+		if company.Profiles == nil {
+			company.Profiles = map[string]string{}
+		}
+		company.Profiles[p.Source] = p.Url
+	}
+
+	counts, err := q.CountCompanyOffers(ctx, companyID)
+	if err != nil {
+		return Company{}, err
+	}
+	company.OpenOffers, company.Jobs = counts.OpenOffers, counts.Jobs
+
+	salaries, err := q.ListCompanySalaries(ctx, companyID)
+	if err != nil {
+		return Company{}, err
+	}
+	company.Salaries = medianSalaries(salaries)
+
+	skills, err := q.CountCompanySkills(ctx, database.CountCompanySkillsParams{CompanyID: companyID, Limit: int64(topSkills)})
+	if err != nil {
+		return Company{}, err
+	}
+	company.TopSkills = make([]SkillCount, 0, len(skills))
+	for _, s := range skills {
+		company.TopSkills = append(company.TopSkills, SkillCount{Skill: s.Skill, Offers: s.Offers})
+	}
+
+	offers, err := q.ListJobOffersByCompany(ctx, companyID)
+	if err != nil {
+		return Company{}, err
+	}
+	company.Offers = make([]scraper.JobOffer, 0, len(offers))
+	for _, o := range offers {
+		company.Offers = append(company.Offers, jobOfferFromRow(o))
+	}
+	return company, nil
+}
+
+// rows come sorted by contract and currency, each run of them is one CompanySalary
+func medianSalaries(rows []database.ListCompanySalariesRow) []CompanySalary {
+	salaries := []CompanySalary{}
+	for start := 0; start < len(rows); {
+		end := start
+		var mins, maxes []float64
+		for ; end < len(rows) && rows[end].ContractType == rows[start].ContractType && rows[end].Currency == rows[start].Currency; end++ {
+			mins = append(mins, rows[end].MonthlyMin)
+			maxes = append(maxes, rows[end].MonthlyMax)
+		}
+		salaries = append(salaries, CompanySalary{
+			Contract:         rows[start].ContractType,
+			Currency:         rows[start].Currency.String,
+			Offers:           end - start,
+			MedianMonthlyMin: median(mins),
+			MedianMonthlyMax: median(maxes),
+		})
+		start = end
+	}
+	return salaries
+}
+
+func median(values []float64) float64 {
+	slices.Sort(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,129 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanySummary(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	offers := []scraper.JobOffer{
+		{Title: "Senior Go Developer", Company: "ACME Sp. z o.o.", CompanyURL: "https://www.pracuj.pl/pracodawca/acme", Source: "pracuj.pl",
+			URL: "https://www.pracuj.pl/praca/senior-go,oferta,1", SalaryB2B: "20 000 - 30 000 PLN net/month", Skills: []string{"Go", "Kafka"}},
+		{Title: "Go Developer", Company: "acme s.a.", CompanyURL: "https://nofluffjobs.com/pl/company/acme", Source: "nofluffjobs.com",
+			URL: "https://nofluffjobs.com/pl/job/go-acme", SalaryB2B: "10 000 - 12 000 PLN net/month", Skills: []string{"Go"}},
+		{Title: "Java Developer", Company: "ACME", Source: "justjoin.it",
+			URL: "https://justjoin.it/job-offer/acme-java", SalaryB2B: "16 000 - 20 000 PLN net/month", Skills: []string{"Java"}},
+		{Title: "Senior Go Developer", Company: "Globex S.A.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/senior-go,oferta,2"},
+		{Title: "Tester", Company: "Sp. z o.o.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/tester,oferta,3"},
+	}
+	for _, job := range offers {
+		require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	}
+
+	// every spelling of acme is one company
+	company, err := CompanySummary(ctx, db, "Acme", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "ACME Sp. z o.o.", company.Name, "the first spelling is the name")
+	assert.ElementsMatch(t, []string{"ACME Sp. z o.o.", "acme s.a.", "ACME"}, company.Aliases)
+	assert.Equal(t, map[string]string{
+		"pracuj.pl":       "https://www.pracuj.pl/pracodawca/acme",
+		"nofluffjobs.com": "https://nofluffjobs.com/pl/company/acme",
+	}, company.Profiles)
+	assert.Equal(t, int64(3), company.OpenOffers)
+	assert.Equal(t, int64(3), company.Jobs)
+	require.Len(t, company.Salaries, 1)
+	assert.Equal(t, 3, company.Salaries[0].Offers)
+	assert.Equal(t, 16000.0, company.Salaries[0].MedianMonthlyMin)
+	assert.Equal(t, 20000.0, company.Salaries[0].MedianMonthlyMax)
+	assert.Equal(t, []SkillCount{{Skill: "Go", Offers: 2}, {Skill: "Java", Offers: 1}}, company.TopSkills)
+	require.Len(t, company.Offers, 3)
+
+	// a closed offer leaves the stats
+	first, err := q.GetJobOfferByUrl(ctx, urlNormalizer(offers[0].URL))
+	require.NoError(t, err)
+	require.NoError(t, q.CloseJobOffer(ctx, first.ID))
+	company, err = CompanySummary(ctx, db, "ACME S.A.", 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), company.OpenOffers)
+	assert.Equal(t, 13000.0, company.Salaries[0].MedianMonthlyMin)
+
+	_, err = CompanySummary(ctx, db, "Initech", 10)
+	assert.ErrorContains(t, err, "no company")
+	// a part of the name finds the only company containing it
+	company, err = CompanySummary(ctx, db, "glob", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "Globex S.A.", company.Name)
+
+	// filtering offers by one spelling finds the offers of every other
+	acme, err := q.FilterJobOffers(ctx, database.FilterJobOffersParams{
+		Company: sql.NullString{String: "acme s.a.", Valid: true}, IncludeClosed: true, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, acme, 3)
+	// wildcards are plain characters, not patterns
+	for _, pattern := range []string{"_", "%", "ac_e"} {
+		matched, err := q.FilterJobOffers(ctx, database.FilterJobOffersParams{
+			Company: sql.NullString{String: pattern, Valid: true}, IncludeClosed: true, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, matched, pattern)
+		_, err = CompanySummary(ctx, db, pattern, 10)
+		assert.ErrorContains(t, err, "no company", pattern)
+	}
+
+	tester, err := q.GetJobOfferByUrl(ctx, urlNormalizer(offers[4].URL))
+	require.NoError(t, err)
+	require.True(t, tester.CompanyID.Valid, "a bare legal form keeps its own key")
+	assert.NotEqual(t, company.ID, tester.CompanyID.Int64)
+}
+
+func TestNormalizeCompany(t *testing.T) {
+	for name, want := range map[string]string{
+		"ACME Sp. z o.o.":          "acme",
+		"ACME sp. z o.o. sp.k.":    "acme",
+		"acme s.a.":                "acme",
+		"ACME Polska Sp. z o.o.":   "acme",
+		"Initech GmbH":             "initech",
+		"K&K":                      "k k",
+		"A.S. Bank":                "a s bank",
+		"Polska Grupa Zbrojeniowa": "polska grupa zbrojeniowa",
+		"Sp. z o.o.":               "sp z o o",
+		" - ":                      "",
+	} {
+		assert.Equal(t, want, normalizeCompany(name), name)
+	}
+}
+
+func TestLinkCompaniesBackfillsOldOffers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	job := scraper.JobOffer{Title: "Go Developer", Company: "Initech Sp. z o.o.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/go,oferta,1"}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	stored, err := q.GetJobOfferByUrl(ctx, urlNormalizer(job.URL))
+	require.NoError(t, err)
+	// as stored before companies existed
+	require.NoError(t, q.SetJobOfferCompany(ctx, database.SetJobOfferCompanyParams{CompanyID: sql.NullInt64{}, ID: stored.ID}))
+
+	linked, err := LinkCompanies(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+	company, err := FindCompany(ctx, db, "initech")
+	require.NoError(t, err)
+	stored, err = q.GetJobOfferByUrl(ctx, urlNormalizer(job.URL))
+	require.NoError(t, err)
+	assert.Equal(t, sql.NullInt64{Int64: company.ID, Valid: true}, stored.CompanyID)
+
+	linked, err = LinkCompanies(ctx, db)
+	require.NoError(t, err)
+	assert.Zero(t, linked)
+}
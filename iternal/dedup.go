@@ -0,0 +1,280 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pfczx/jobscraper/database"
+)
+
+// PostingLink is one board's offer of a job
+type PostingLink struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+const (
+	// title token overlap that alone makes two offers of a company the same job
+	sameTitle = 0.75
+	// a looser title match needs descriptions this alike
+	similarTitle       = 0.5
+	similarDescription = 0.4
+	// descriptions this far apart are different jobs even under the same title
+	differentDescription = 0.15
+)
+
+// title words boards spell differently for the same role
+var titleAliases = map[string]string{
+	"golang":      "go",
+	"js":          "javascript",
+	"ts":          "typescript",
+	"engineer":    "developer",
+	"programmer":  "developer",
+	"programista": "developer",
+	"inżynier":    "developer",
+	"dev":         "developer",
+}
+
+// seniority words, offers of different levels are different jobs
+var titleLevels = map[string]string{
+	"intern": "intern", "internship": "intern", "stażysta": "intern", "staż": "intern",
+	"junior": "junior", "młodszy": "junior",
+	"mid": "mid", "regular": "mid",
+	"senior": "senior", "starszy": "senior",
+	"lead": "lead", "principal": "lead", "staff": "lead", "head": "lead",
+}
+
+// "(k/m)", "(m/f/d)" and similar
+var genderMarkerRe = regexp.MustCompile(`\([a-zżźćńółęąś]{1,3}(?:/[a-zżźćńółęąś]{1,3})+\)`)
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// posting is what dedupe compares of one stored offer
+type posting struct {
+	id          string
+	group       string
+	company     string
+	level       string
+	title       map[string]bool
+	description map[string]bool
+	cities      map[string]bool
+	remote      bool
+	open        bool
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// title words with aliases resolved and the seniority taken out
+func normalizeTitle(title string) (tokens map[string]bool, level string) {
+	tokens = map[string]bool{}
+	for _, w := range words(genderMarkerRe.ReplaceAllString(strings.ToLower(title), " ")) {
+		if l, ok := titleLevels[w]; ok {
+			level = l
+			continue
+		}
+		if alias, ok := titleAliases[w]; ok {
+			w = alias
+		}
+		tokens[w] = true
+	}
+	return tokens, level
+}
+
+func descriptionTokens(description string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, w := range words(htmlTagRe.ReplaceAllString(description, " ")) {
+		if len([]rune(w)) >= 3 {
+			tokens[w] = true
+		}
+	}
+	return tokens
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// samePosting tells whether two offers of a company describe one job
+func samePosting(a, b posting) bool {
+	if a.company == "" || a.company != b.company {
+		return false
+	}
+	if a.level != "" && b.level != "" && a.level != b.level {
+		return false
+	}
+	// a remote offer may name the office on one board only
+	if len(a.cities) > 0 && len(b.cities) > 0 && !a.remote && !b.remote && jaccard(a.cities, b.cities) == 0 {
+		return false
+	}
+
+	title := jaccard(a.title, b.title)
+	described := len(a.description) > 0 && len(b.description) > 0
+	switch {
+	case title >= sameTitle:
+		return !described || jaccard(a.description, b.description) >= differentDescription
+	case title >= similarTitle:
+		return described && jaccard(a.description, b.description) >= similarDescription
+	}
+	return false
+}
+
+// groups of offer indexes, two groups only merge when every pair of their offers matches,
+// so a vague "Go Developer" can't chain a junior and a senior offer into one job
+type groups struct {
+	root    []int
+	members map[int][]int
+}
+
+func newGroups(n int) *groups {
+	g := &groups{root: make([]int, n), members: make(map[int][]int, n)}
+	for i := range g.root {
+		g.root[i] = i
+		g.members[i] = []int{i}
+	}
+	return g
+}
+
+func (g *groups) merge(i, j int, same func(a, b int) bool) {
+	ri, rj := g.root[i], g.root[j]
+	if ri == rj {
+		return
+	}
+	for _, a := range g.members[ri] {
+		for _, b := range g.members[rj] {
+			if !same(a, b) {
+				return
+			}
+		}
+	}
+	// the older offer stays the root
+	if rj < ri {
+		ri, rj = rj, ri
+	}
+	for _, m := range g.members[rj] {
+		g.root[m] = ri
+	}
+	g.members[ri] = append(g.members[ri], g.members[rj]...)
+	delete(g.members, rj)
+}
+
+// GroupPostings clusters every stored offer with the offers of the same job on other boards,
+// by company, title, location and description, and stores the id of each group's canonical
+// offer, the oldest open one, as posting_group_id. Returns the number of jobs and of offers
+// that duplicate another one.
+func GroupPostings(ctx context.Context, db *sql.DB) (jobs, duplicates int, err error) {
+	q := database.New(db)
+	rows, err := q.ListPostingCandidates(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	cityRows, err := q.ListPostingCandidateCities(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	cities := map[string]map[string]bool{}
+	for _, c := range cityRows {
+		if cities[c.JobOfferID] == nil {
+			cities[c.JobOfferID] = map[string]bool{}
+		}
+		cities[c.JobOfferID][c.City] = true
+	}
+
+	postings := make([]posting, len(rows))
+	byCompany := map[string][]int{}
+	for i, row := range rows {
+		p := posting{
+			id:          row.ID,
+			group:       row.PostingGroupID.String,
+			company:     normalizeCompany(row.Company.String),
+			description: descriptionTokens(row.Description.String),
+			cities:      cities[row.ID],
+			remote:      row.WorkMode.String == "remote",
+			open:        !row.ClosedAt.Valid,
+		}
+		p.title, p.level = normalizeTitle(row.Title)
+		postings[i] = p
+		if p.company != "" {
+			byCompany[p.company] = append(byCompany[p.company], i)
+		}
+	}
+
+	same := func(a, b int) bool { return samePosting(postings[a], postings[b]) }
+	g := newGroups(len(postings))
+	for _, offers := range byCompany {
+		for x, i := range offers {
+			for _, j := range offers[x+1:] {
+				if same(i, j) {
+					g.merge(i, j, same)
+				}
+			}
+		}
+	}
+
+	// rows come oldest first, so the first open member of a group is its canonical offer
+	canonical := map[int]int{}
+	for i, p := range postings {
+		root := g.root[i]
+		c, ok := canonical[root]
+		if !ok {
+			jobs++
+			canonical[root] = i
+		} else {
+			duplicates++
+			if !postings[c].open && p.open {
+				canonical[root] = i
+			}
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+	querier := q.WithTx(tx)
+	for i, p := range postings {
+		group := postings[canonical[g.root[i]]].id
+		if group == p.group {
+			continue
+		}
+		params := database.SetJobOfferPostingGroupParams{PostingGroupID: sql.NullString{String: group, Valid: true}, ID: p.id}
+		if err := querier.SetJobOfferPostingGroup(ctx, params); err != nil {
+			return 0, 0, err
+		}
+	}
+	return jobs, duplicates, tx.Commit()
+}
+
+// postingLinks lists every board's offer of the job row belongs to, row alone before dedupe ran
+func postingLinks(ctx context.Context, q database.Querier, row database.JobOffer) ([]PostingLink, error) {
+	if !row.PostingGroupID.Valid {
+		return []PostingLink{{ID: row.ID, Source: row.Source, URL: row.Url, Closed: row.ClosedAt.Valid}}, nil
+	}
+	rows, err := q.ListPostingGroupOffers(ctx, row.PostingGroupID)
+	if err != nil {
+		return nil, err
+	}
+	links := make([]PostingLink, 0, len(rows))
+	for _, r := range rows {
+		links = append(links, PostingLink{ID: r.ID, Source: r.Source, URL: r.Url, Closed: r.ClosedAt.Valid})
+	}
+	return links, nil
+}
@@ -0,0 +1,94 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupPostingsAcrossSources(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+
+	description := "<p>Budujemy platformę płatności obsługującą miliony transakcji dziennie.</p><ul><li>bardzo dobra znajomość Go</li><li>projektowanie mikroserwisów</li></ul>"
+	krakow := scraper.Location{Cities: []string{"Kraków"}, Country: "PL", WorkMode: scraper.WorkModeHybrid}
+	offers := []scraper.JobOffer{
+		{Title: "Senior Go Developer", Company: "ACME Sp. z o.o.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/senior-go,oferta,1",
+			Description: description, Place: krakow, Skills: []string{"Go"}},
+		{Title: "Senior Golang Engineer (k/m)", Company: "ACME", Source: "nofluffjobs.com", URL: "https://nofluffjobs.com/pl/job/senior-golang-acme",
+			Description: description + "<p>Praca hybrydowa w Krakowie.</p>", Place: krakow, Skills: []string{"Go"}},
+		{Title: "Go Developer", Company: "Acme Polska", Source: "justjoin.it", URL: "https://justjoin.it/job-offer/acme-go-developer",
+			Place: krakow, Skills: []string{"Go"}},
+		// same company, another level
+		{Title: "Junior Go Developer", Company: "ACME Sp. z o.o.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/junior-go,oferta,2",
+			Place: krakow, Skills: []string{"Go"}},
+		// same title at another company
+		{Title: "Senior Go Developer", Company: "Globex S.A.", Source: "pracuj.pl", URL: "https://www.pracuj.pl/praca/senior-go,oferta,3",
+			Description: description, Place: krakow, Skills: []string{"Go"}},
+		// same company and title in another city
+		{Title: "Senior Go Developer", Company: "ACME", Source: "nofluffjobs.com", URL: "https://nofluffjobs.com/pl/job/senior-go-acme-gdansk",
+			Place: scraper.Location{Cities: []string{"Gdańsk"}, Country: "PL", WorkMode: scraper.WorkModeOnsite}, Skills: []string{"Go"}},
+	}
+	for _, job := range offers {
+		require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	}
+
+	jobs, duplicates, err := GroupPostings(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 4, jobs)
+	assert.Equal(t, 2, duplicates)
+
+	first, err := q.GetJobOfferByUrl(ctx, urlNormalizer(offers[0].URL))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, first.PostingGroupID.String, "the oldest offer is canonical")
+	links, err := postingLinks(ctx, q, first)
+	require.NoError(t, err)
+	var sources []string
+	for _, l := range links {
+		sources = append(sources, l.Source)
+	}
+	assert.Equal(t, []string{"pracuj.pl", "nofluffjobs.com", "justjoin.it"}, sources)
+
+	page, err := q.FilterJobOffers(ctx, database.FilterJobOffersParams{OnePerGroup: true, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, page, 4)
+	count, err := q.CountFilteredJobOffers(ctx, database.CountFilteredJobOffersParams{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), count, "without grouping every posting counts")
+
+	skills, err := q.CountJobOffersBySkill(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, skills, 1)
+	assert.Equal(t, int64(4), skills[0].Offers, "a job posted on three boards counts once")
+
+	// the group is represented by its offer on the filtered board, not dropped with the canonical one
+	justjoin := sql.NullString{String: "justjoin.it", Valid: true}
+	page, err = q.FilterJobOffers(ctx, database.FilterJobOffersParams{Source: justjoin, OnePerGroup: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, urlNormalizer(offers[2].URL), page[0].Url)
+	count, err = q.CountFilteredJobOffers(ctx, database.CountFilteredJobOffersParams{Source: justjoin, OnePerGroup: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	nofluff := sql.NullString{String: "nofluffjobs.com", Valid: true}
+	count, err = q.CountFilteredJobOffers(ctx, database.CountFilteredJobOffersParams{Source: nofluff, OnePerGroup: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "the gdańsk offer is a job of its own")
+
+	// a closed canonical offer hands over to the oldest open one
+	require.NoError(t, q.CloseJobOffer(ctx, first.ID))
+	_, _, err = GroupPostings(ctx, db)
+	require.NoError(t, err)
+	second, err := q.GetJobOfferByUrl(ctx, urlNormalizer(offers[1].URL))
+	require.NoError(t, err)
+	assert.Equal(t, sql.NullString{String: second.ID, Valid: true}, second.PostingGroupID)
+	page, err = q.FilterJobOffers(ctx, database.FilterJobOffersParams{OnePerGroup: true, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, page, 4)
+}
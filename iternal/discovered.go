@@ -0,0 +1,90 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/pfczx/jobscraper/database"
+)
+
+// scrape status of a discovered url
+const (
+	URLPending = "pending"
+	URLScraped = "scraped"
+	URLFailed  = "failed"
+	URLClosed  = "closed"
+)
+
+// SaveDiscoveredURLs stores the urls a collect found for source, normalized and deduplicated,
+// new ones wait for their first scrape. With closeMissing the stored urls of source missing
+// from urls are closed so scrape skips them, an empty listing closes nothing.
+func SaveDiscoveredURLs(ctx context.Context, db *sql.DB, source string, urls []string, closeMissing bool) (added, closed int, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+	querier := database.New(db).WithTx(tx)
+
+	stored, err := querier.ListDiscoveredUrls(ctx, source)
+	if err != nil {
+		return 0, 0, err
+	}
+	known := make(map[string]bool, len(stored))
+	for _, row := range stored {
+		known[row.Url] = true
+	}
+
+	listed := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		url = urlNormalizer(url)
+		if listed[url] {
+			continue
+		}
+		listed[url] = true
+		if !known[url] {
+			added++
+		}
+		if err := querier.UpsertDiscoveredUrl(ctx, database.UpsertDiscoveredUrlParams{Url: url, Source: source}); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if closeMissing && len(listed) > 0 {
+		for _, row := range stored {
+			if listed[row.Url] || row.Status == URLClosed {
+				continue
+			}
+			if err := querier.SetDiscoveredUrlStatus(ctx, database.SetDiscoveredUrlStatusParams{Status: URLClosed, Url: row.Url}); err != nil {
+				return 0, 0, err
+			}
+			closed++
+		}
+	}
+	return added, closed, tx.Commit()
+}
+
+// LoadDiscoveredURLs returns the urls of source scrape should visit, everything not closed
+// in the order it was first discovered
+func LoadDiscoveredURLs(ctx context.Context, db *sql.DB, source string) ([]string, error) {
+	rows, err := database.New(db).ListDiscoveredUrls(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Status != URLClosed {
+			urls = append(urls, row.Url)
+		}
+	}
+	return urls, nil
+}
+
+// records how the last scrape of url went, urls never discovered (read from files) are left alone
+func setURLStatus(ctx context.Context, db *sql.DB, url, status string) {
+	params := database.SetDiscoveredUrlStatusParams{Status: status, Url: urlNormalizer(url)}
+	if err := database.New(db).SetDiscoveredUrlStatus(ctx, params); err != nil {
+		log.Printf("Error %s in updating status of %s", err, url)
+	}
+}
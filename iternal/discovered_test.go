@@ -0,0 +1,52 @@
+package iternal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveredURLs(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	a, b, c := "https://nofluffjobs.com/pl/job/a", "https://nofluffjobs.com/pl/job/b", "https://nofluffjobs.com/pl/job/c"
+
+	added, closed, err := SaveDiscoveredURLs(ctx, db, "nofluffjobs.com", []string{a + "?utm_source=list", b, a + "/"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added, "normalized duplicates count once")
+	assert.Zero(t, closed)
+
+	// b left the listing, c is new
+	added, closed, err = SaveDiscoveredURLs(ctx, db, "nofluffjobs.com", []string{a, c}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, closed)
+
+	urls, err := LoadDiscoveredURLs(ctx, db, "nofluffjobs.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, c}, urls)
+
+	setURLStatus(ctx, db, a+"?ref=x", URLScraped)
+	setURLStatus(ctx, db, c, URLFailed)
+	rows, err := database.New(db).ListDiscoveredUrls(ctx, "nofluffjobs.com")
+	require.NoError(t, err)
+	status := map[string]string{}
+	for _, row := range rows {
+		status[row.Url] = row.Status
+	}
+	assert.Equal(t, map[string]string{a: URLScraped, b: URLClosed, c: URLFailed}, status)
+	assert.True(t, rows[0].ScrapedAt.Valid)
+
+	// listed again, b waits for a scrape; an empty listing closes nothing
+	_, _, err = SaveDiscoveredURLs(ctx, db, "nofluffjobs.com", []string{b}, false)
+	require.NoError(t, err)
+	_, closed, err = SaveDiscoveredURLs(ctx, db, "nofluffjobs.com", nil, true)
+	require.NoError(t, err)
+	assert.Zero(t, closed)
+	urls, err = LoadDiscoveredURLs(ctx, db, "nofluffjobs.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, b, c}, urls)
+}
@@ -0,0 +1,69 @@
+package embedding
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/pfczx/jobscraper/config"
+)
+
+// Embedder turns texts into vectors, Model identifies the vector space so
+// vectors from different embedders are never compared
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	Model() string
+}
+
+// New builds the embedder from config, nil for the "none" provider
+func New(cfg config.Embedding) (Embedder, error) {
+	switch cfg.Provider {
+	case config.EmbedderNone:
+		return nil, nil
+	case config.EmbedderHash:
+		return NewHash(cfg.Dims), nil
+	case config.EmbedderHTTP:
+		return NewHTTP(&http.Client{Timeout: cfg.Timeout}, cfg.URL, cfg.Model, cfg.APIKey), nil
+	}
+	return nil, fmt.Errorf("unknown embedding provider %q", cfg.Provider)
+}
+
+// Encode stores a vector as little endian float32s for the embedding BLOB column
+func Encode(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func Decode(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.New("embedding blob length is not a multiple of 4")
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v, nil
+}
+
+// Cosine returns the cosine similarity, 0 for zero vectors or different sizes
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,64 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	v := []float32{0.5, -1.25, 3}
+	got, err := Decode(Encode(v))
+	require.NoError(t, err)
+	assert.Equal(t, v, got)
+
+	_, err = Decode([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestHashSimilarity(t *testing.T) {
+	h := NewHash(512)
+	vs, err := h.Embed(context.Background(), []string{
+		"Senior Go developer, Kubernetes, PostgreSQL",
+		"Golang backend: Go, PostgreSQL and Kubernetes in production",
+		"Księgowa, znajomość Excel i SAP",
+	})
+	require.NoError(t, err)
+	require.Len(t, vs, 3)
+
+	assert.InDelta(t, 1.0, Cosine(vs[0], vs[0]), 1e-6)
+	assert.Greater(t, Cosine(vs[0], vs[1]), Cosine(vs[0], vs[2]))
+	assert.Equal(t, "hash-bow-512", h.Model())
+}
+
+func TestHTTPEmbedder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var req embedRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+		// answer out of order, index decides the position
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	e := NewHTTP(srv.Client(), srv.URL, "test-model", "secret")
+	vs, err := e.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vs)
+}
+
+func TestHTTPEmbedderStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewHTTP(srv.Client(), srv.URL, "m", "").Embed(context.Background(), []string{"a"})
+	assert.ErrorContains(t, err, "model not loaded")
+}
@@ -0,0 +1,78 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Hash is an offline embedder: hashed bag of words with sublinear term frequency.
+// It only captures shared vocabulary, good enough to find offers for the same stack.
+type Hash struct {
+	dims int
+}
+
+func NewHash(dims int) *Hash {
+	return &Hash{dims: dims}
+}
+
+func (h *Hash) Model() string {
+	return fmt.Sprintf("hash-bow-%d", h.dims)
+}
+
+func (h *Hash) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+func (h *Hash) embed(text string) []float32 {
+	counts := make(map[string]int)
+	for _, token := range tokenize(text) {
+		counts[token]++
+	}
+
+	v := make([]float32, h.dims)
+	for token, n := range counts {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(token))
+		sum := hasher.Sum64()
+		// the top bit picks the sign so colliding tokens tend to cancel out instead of piling up
+		weight := float32(1 + math.Log(float64(n)))
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		v[sum%uint64(h.dims)] += weight
+	}
+
+	var norm float64
+	for _, f := range v {
+		norm += float64(f) * float64(f)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range v {
+			v[i] *= scale
+		}
+	}
+	return v
+}
+
+// lowercase words, keeps + and # so c++ and c# don't collapse into c
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	tokens := words[:0]
+	for _, w := range words {
+		if len([]rune(w)) > 1 || w == "c" || w == "r" {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,155 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+const exportPageSize = 500
+
+// exportOffer is an offer with the offers of the same job on other boards
+type exportOffer struct {
+	scraper.JobOffer
+	Postings []PostingLink `json:"postings,omitempty"`
+}
+
+// converts stored row back to the scraper model used in exports
+func jobOfferFromRow(row database.JobOffer) scraper.JobOffer {
+	job := scraper.JobOffer{
+		ID:               row.ID,
+		Title:            row.Title,
+		Company:          row.Company.String,
+		Location:         row.Location.String,
+		SalaryEmployment: row.SalaryEmployment.String,
+		SalaryContract:   row.SalaryContract.String,
+		SalaryB2B:        row.SalaryB2b.String,
+		Description:      row.Description.String,
+		URL:              row.Url,
+		Source:           row.Source,
+		Place: scraper.Location{
+			Country:  row.Country.String,
+			WorkMode: scraper.WorkMode(row.WorkMode.String),
+		},
+	}
+	// offers whose page showed no date count as published when first scraped
+	switch {
+	case row.PublishedAt.Valid:
+		job.PublishedAt = &row.PublishedAt.Time
+	case row.CreatedAt.Valid:
+		job.PublishedAt = &row.CreatedAt.Time
+	}
+	if row.ExpiresAt.Valid {
+		job.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.ClosedAt.Valid {
+		closed := row.ClosedAt.Time.Format(time.RFC3339)
+		job.ClosedAt = &closed
+	}
+	if row.Skills.Valid {
+		_ = json.Unmarshal([]byte(row.Skills.String), &job.Skills)
+	}
+	return job
+}
+
+// pages through job_offers, optionally only one source, closed offers only when asked for,
+// with onePerGroup only the canonical offer of every job
+func listAllJobOffers(ctx context.Context, q database.Querier, source string, includeClosed, onePerGroup bool) ([]database.JobOffer, error) {
+	var all []database.JobOffer
+	for offset := int64(0); ; offset += exportPageSize {
+		page, err := q.FilterJobOffers(ctx, database.FilterJobOffersParams{
+			Source:        sql.NullString{String: source, Valid: source != ""},
+			IncludeClosed: includeClosed,
+			OnePerGroup:   onePerGroup,
+			Limit:         exportPageSize,
+			Offset:        offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < exportPageSize {
+			return all, nil
+		}
+	}
+}
+
+// ExportJobOffers writes stored offers to w as "json" or "csv", returns number of offers written.
+// Closed offers are left out unless includeClosed is set, grouped writes one offer per job.
+// Every offer lists the urls of its job on all boards.
+func ExportJobOffers(ctx context.Context, db *sql.DB, w io.Writer, format string, source string, includeClosed, grouped bool) (int, error) {
+	q := database.New(db)
+	rows, err := listAllJobOffers(ctx, q, source, includeClosed, grouped)
+	if err != nil {
+		return 0, err
+	}
+
+	jobs := make([]exportOffer, 0, len(rows))
+	for _, row := range rows {
+		job := exportOffer{JobOffer: jobOfferFromRow(row)}
+		if job.Place.Cities, err = q.ListJobOfferCities(ctx, row.ID); err != nil {
+			return 0, err
+		}
+		if job.Postings, err = postingLinks(ctx, q, row); err != nil {
+			return 0, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(jobs); err != nil {
+			return 0, err
+		}
+	case "csv":
+		cw := csv.NewWriter(w)
+		header := []string{"id", "title", "company", "location", "cities", "country", "work_mode", "salary_employment", "salary_contract", "salary_b2b", "url", "source", "published_at", "skills", "closed_at", "expires_at", "posting_urls"}
+		if err := cw.Write(header); err != nil {
+			return 0, err
+		}
+		for _, job := range jobs {
+			published, closed, expires := "", "", ""
+			if job.PublishedAt != nil {
+				published = job.PublishedAt.Format(time.RFC3339)
+			}
+			if job.ClosedAt != nil {
+				closed = *job.ClosedAt
+			}
+			if job.ExpiresAt != nil {
+				expires = job.ExpiresAt.Format(time.RFC3339)
+			}
+			urls := make([]string, 0, len(job.Postings))
+			for _, link := range job.Postings {
+				urls = append(urls, link.URL)
+			}
+			record := []string{
+				job.ID, job.Title, job.Company, job.Location,
+				strings.Join(job.Place.Cities, ";"), job.Place.Country, string(job.Place.WorkMode),
+				job.SalaryEmployment, job.SalaryContract, job.SalaryB2B,
+				job.URL, job.Source, published, strings.Join(job.Skills, ";"), closed, expires,
+				strings.Join(urls, ";"),
+			}
+			if err := cw.Write(record); err != nil {
+				return 0, err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	return len(jobs), nil
+}
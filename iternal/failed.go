@@ -0,0 +1,147 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// FailedURL is a url a scrape could not turn into an offer, kept for retry-failed
+type FailedURL struct {
+	URL           string    `json:"url"`
+	Source        string    `json:"source"`
+	Error         string    `json:"error"`
+	Permanent     bool      `json:"permanent"`
+	Attempts      int       `json:"attempts"`
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+// NextAttempt is the earliest retry, backoff after the first failure and doubled after every further one
+func (f FailedURL) NextAttempt(backoff time.Duration) time.Time {
+	wait := backoff
+	for i := 1; i < f.Attempts && wait < 30*24*time.Hour; i++ {
+		wait *= 2
+	}
+	return f.LastAttemptAt.Add(wait)
+}
+
+// a transient failure with attempts left whose backoff passed by now
+func (f FailedURL) due(backoff time.Duration, maxAttempts int, now time.Time) bool {
+	return !f.Permanent && f.Attempts < maxAttempts && !f.NextAttempt(backoff).After(now)
+}
+
+// client errors won't change on a retry, except for the ones a board uses to push back
+func permanentFailure(err error) bool {
+	var statusErr *fetcher.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode < 400 || statusErr.StatusCode >= 500 {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// files the failed url of job, counting the attempts it failed so far
+func recordFailure(ctx context.Context, db *sql.DB, job scraper.JobOffer) error {
+	q := database.New(db)
+	now := time.Now().UTC()
+	params := database.UpsertFailedUrlParams{
+		Url:           job.URL,
+		Source:        job.Source,
+		Error:         job.Err.Error(),
+		Permanent:     permanentFailure(job.Err),
+		Attempts:      1,
+		FirstFailedAt: now,
+		LastAttemptAt: now,
+	}
+	stored, err := q.GetFailedUrl(ctx, job.URL)
+	switch {
+	case err == nil:
+		params.Attempts = stored.Attempts + 1
+		params.FirstFailedAt = stored.FirstFailedAt
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+	return q.UpsertFailedUrl(ctx, params)
+}
+
+// a url that got scraped after all leaves the queue, a stale entry only means a needless retry
+func clearFailure(ctx context.Context, db *sql.DB, url string) {
+	if err := database.New(db).DeleteFailedUrl(ctx, url); err != nil {
+		log.Printf("Error %s in clearing failed url: %s", err, url)
+	}
+}
+
+// ListFailedURLs returns the failed urls of source, every source when empty, transient ones first
+func ListFailedURLs(ctx context.Context, db *sql.DB, source string) ([]FailedURL, error) {
+	rows, err := database.New(db).ListFailedUrls(ctx, sql.NullString{String: source, Valid: source != ""})
+	if err != nil {
+		return nil, err
+	}
+	failed := make([]FailedURL, 0, len(rows))
+	for _, row := range rows {
+		failed = append(failed, FailedURL{
+			URL:           row.Url,
+			Source:        row.Source,
+			Error:         row.Error,
+			Permanent:     row.Permanent,
+			Attempts:      int(row.Attempts),
+			FirstFailedAt: row.FirstFailedAt,
+			LastAttemptAt: row.LastAttemptAt,
+		})
+	}
+	return failed, nil
+}
+
+// DueFailedURLs returns the transient failures of source with attempts left whose backoff
+// passed by now, the longest waiting first
+func DueFailedURLs(ctx context.Context, db *sql.DB, source string, backoff time.Duration, maxAttempts int, now time.Time) ([]FailedURL, error) {
+	failed, err := ListFailedURLs(ctx, db, source)
+	if err != nil {
+		return nil, err
+	}
+	var due []FailedURL
+	for _, f := range failed {
+		if f.due(backoff, maxAttempts, now) {
+			due = append(due, f)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttempt(backoff).Before(due[j].NextAttempt(backoff)) })
+	return due, nil
+}
+
+// SkipFailed drops the urls of source filed as failed that are not due for a retry,
+// a scrape leaves them to the backoff of retry-failed
+func SkipFailed(ctx context.Context, db *sql.DB, source string, urls []string, backoff time.Duration, maxAttempts int, now time.Time) ([]string, error) {
+	failed, err := ListFailedURLs(ctx, db, source)
+	if err != nil {
+		return nil, err
+	}
+	held := map[string]bool{}
+	for _, f := range failed {
+		if !f.due(backoff, maxAttempts, now) {
+			held[urlNormalizer(f.URL)] = true
+		}
+	}
+	if len(held) == 0 {
+		return urls, nil
+	}
+	kept := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if !held[urlNormalizer(url)] {
+			kept = append(kept, url)
+		}
+	}
+	return kept, nil
+}
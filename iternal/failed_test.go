@@ -0,0 +1,81 @@
+package iternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedURLQueue(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	crashed := scraper.JobOffer{URL: "https://justjoin.it/job-offer/a", Source: "justjoin.it", Err: errors.New("browser crashed")}
+	gone := scraper.JobOffer{
+		URL:     "https://justjoin.it/job-offer/b",
+		Source:  "justjoin.it",
+		Expired: true,
+		Err:     fmt.Errorf("fetch failed 3 times: %w", &fetcher.StatusError{URL: "https://justjoin.it/job-offer/b", StatusCode: 404}),
+	}
+
+	require.NoError(t, recordFailure(ctx, db, crashed))
+	require.NoError(t, recordFailure(ctx, db, crashed))
+	require.NoError(t, recordFailure(ctx, db, gone))
+
+	failed, err := ListFailedURLs(ctx, db, "justjoin.it")
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, crashed.URL, failed[0].URL, "transient first")
+	assert.Equal(t, 2, failed[0].Attempts)
+	assert.Equal(t, "browser crashed", failed[0].Error)
+	assert.False(t, failed[0].Permanent)
+	assert.True(t, failed[1].Permanent)
+
+	// second failure doubles the backoff
+	last := failed[0].LastAttemptAt
+	assert.Equal(t, last.Add(2*time.Hour), failed[0].NextAttempt(time.Hour))
+
+	due, err := DueFailedURLs(ctx, db, "justjoin.it", time.Hour, 5, last.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = DueFailedURLs(ctx, db, "justjoin.it", time.Hour, 5, last.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 1, "permanent failures are never due")
+	assert.Equal(t, crashed.URL, due[0].URL)
+	due, err = DueFailedURLs(ctx, db, "justjoin.it", 0, 2, last)
+	require.NoError(t, err)
+	assert.Empty(t, due, "out of attempts")
+
+	// a scrape leaves the held back and permanent failures alone
+	listed := []string{crashed.URL, gone.URL, "https://justjoin.it/job-offer/c"}
+	urls, err := SkipFailed(ctx, db, "justjoin.it", listed, time.Hour, 5, last.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, listed[2:], urls)
+	urls, err = SkipFailed(ctx, db, "justjoin.it", listed, time.Hour, 5, last.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{crashed.URL, listed[2]}, urls)
+
+	other, err := ListFailedURLs(ctx, db, "pracuj.pl")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	clearFailure(ctx, db, crashed.URL)
+	failed, err = ListFailedURLs(ctx, db, "")
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, gone.URL, failed[0].URL)
+}
+
+func TestPermanentFailure(t *testing.T) {
+	assert.True(t, permanentFailure(&fetcher.StatusError{StatusCode: 404}))
+	assert.True(t, permanentFailure(&fetcher.StatusError{StatusCode: 410}))
+	assert.False(t, permanentFailure(&fetcher.StatusError{StatusCode: 429}))
+	assert.False(t, permanentFailure(&fetcher.StatusError{StatusCode: 503}))
+	assert.False(t, permanentFailure(errors.New("no title found")))
+}
@@ -0,0 +1,189 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/url"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+	"github.com/pfczx/jobscraper/config"
+)
+
+// Chrome fetches pages with a real browser, one instance per fetcher so every
+// source keeps its own profile (cookies, cloudflare clearance)
+type Chrome struct {
+	ctx         context.Context
+	opts        []chromedp.ExecAllocatorOption
+	pool        *Pool
+	proxy       *url.URL
+	browserCtx  context.Context
+	cancelAlloc context.CancelFunc
+	cancelCtx   context.CancelFunc
+}
+
+// NewChrome prepares a browser bound to ctx, chrome itself starts on the first fetch.
+// With a proxy pool the browser keeps one proxy per session and restarts on the next one
+// once it gets evicted.
+func NewChrome(ctx context.Context, browser config.Browser, dataDir string, pool *Pool) *Chrome {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+		chromedp.Flag("headless", browser.Headless),
+		chromedp.Flag("disable-gpu", false),
+		chromedp.UserAgent(browser.UserAgent),
+		chromedp.Flag("disable-web-security", true),
+		chromedp.Flag("disable-site-isolation-trials", true),
+		//flags for ANR
+		chromedp.Flag("disable-background-timer-throttling", true),
+		chromedp.Flag("disable-renderer-backgrounding", true),
+		chromedp.Flag("disable-backgrounding-occluded-windows", true),
+		chromedp.Flag("disable-ipc-flooding-protection", true),
+	)
+	if browser.ExecPath != "" {
+		opts = append(opts, chromedp.ExecPath(browser.ExecPath))
+	}
+	if dataDir != "" {
+		opts = append(opts, chromedp.UserDataDir(dataDir))
+	}
+
+	return &Chrome{ctx: ctx, opts: opts, pool: pool}
+}
+
+// starts the browser on first use, or again through another proxy when the current one was evicted
+func (c *Chrome) session() (context.Context, error) {
+	if c.browserCtx != nil && c.pool.Usable(c.proxy) {
+		return c.browserCtx, nil
+	}
+	proxy, err := c.pool.pick(c.proxy, true)
+	if err != nil {
+		return nil, err
+	}
+	if c.browserCtx != nil {
+		log.Printf("Restarting browser with proxy %s", proxy.Redacted())
+		c.Close()
+	}
+
+	opts := c.opts
+	if proxy != nil {
+		// chrome takes no credentials in the proxy flag, proxyAuth answers its challenges
+		opts = append(opts[:len(opts):len(opts)], chromedp.ProxyServer(proxy.Scheme+"://"+proxy.Host))
+	}
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(c.ctx, opts...)
+	c.browserCtx, c.cancelCtx = chromedp.NewContext(allocCtx)
+	c.cancelAlloc = cancelAlloc
+	c.proxy = proxy
+	if proxy != nil && proxy.User != nil {
+		if err := proxyAuth(c.browserCtx, proxy.User); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("proxy %s: %w", proxy.Redacted(), err)
+		}
+	}
+	return c.browserCtx, nil
+}
+
+// answers the proxy's auth challenges with the credentials of its url, the fetch domain
+// pauses every request once enabled so the rest are let through untouched
+func proxyAuth(browserCtx context.Context, user *url.Userinfo) error {
+	password, _ := user.Password()
+	chromedp.ListenTarget(browserCtx, func(ev any) {
+		switch ev := ev.(type) {
+		case *fetch.EventAuthRequired:
+			answer := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+			if ev.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+				answer = &fetch.AuthChallengeResponse{
+					Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+					Username: user.Username(),
+					Password: password,
+				}
+			}
+			go chromedp.Run(browserCtx, fetch.ContinueWithAuth(ev.RequestID, answer))
+		case *fetch.EventRequestPaused:
+			go chromedp.Run(browserCtx, fetch.ContinueRequest(ev.RequestID))
+		}
+	})
+	return chromedp.Run(browserCtx, fetch.Enable().WithHandleAuthRequests(true))
+}
+
+func (c *Chrome) Fetch(ctx context.Context, url string) (Page, error) {
+	browserCtx, err := c.session()
+	if err != nil {
+		return Page{}, err
+	}
+	page, err := c.fetch(ctx, browserCtx, url)
+	c.pool.Report(c.proxy, !proxyFailed(ctx, err))
+	return page, err
+}
+
+func (c *Chrome) fetch(ctx, browserCtx context.Context, url string) (Page, error) {
+	// stop the fetch when either the caller or the browser is done
+	runCtx, cancel := context.WithCancel(browserCtx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	resp, err := chromedp.RunResponse(runCtx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			return emulation.SetDeviceMetricsOverride(1280, 900, 1.0, false).Do(ctx)
+		}),
+		chromedp.Navigate(url),
+	)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var page Page
+	err = chromedp.Run(runCtx,
+		chromedp.Evaluate(`delete navigator.__proto__.webdriver`, nil),
+		chromedp.Evaluate(`Object.defineProperty(navigator, "webdriver", { get: () => false })`, nil),
+		chromedp.Sleep(time.Duration(rand.Intn(800)+300)*time.Millisecond),
+		chromedp.WaitVisible("body", chromedp.ByQuery),
+		chromedp.Location(&page.FinalURL),
+		chromedp.OuterHTML("html", &page.HTML),
+	)
+	if err != nil {
+		return Page{}, err
+	}
+	if resp != nil {
+		page.StatusCode = int(resp.Status)
+	}
+	if page.StatusCode >= 400 {
+		return page, &StatusError{URL: url, StatusCode: page.StatusCode}
+	}
+	return page, nil
+}
+
+// Run executes custom actions (scrolling, clicking) in the fetcher's tab,
+// used by url collectors that need more than a page load
+func (c *Chrome) Run(ctx context.Context, actions ...chromedp.Action) error {
+	browserCtx, err := c.session()
+	if err != nil {
+		return err
+	}
+	runCtx, cancel := context.WithCancel(browserCtx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	return chromedp.Run(runCtx, actions...)
+}
+
+func (c *Chrome) Close() error {
+	if c.browserCtx == nil {
+		return nil
+	}
+	c.cancelCtx()
+	c.cancelAlloc()
+	c.browserCtx = nil
+	return nil
+}
+
+// Browser is a fetcher that can also drive the page, infinite scroll listings need it
+type Browser interface {
+	Fetcher
+	Run(ctx context.Context, actions ...chromedp.Action) error
+}
+
+var _ Browser = (*Chrome)(nil)
@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+)
+
+// Page is a fetched document, FinalURL differs from the requested url after redirects
+type Page struct {
+	HTML       string
+	StatusCode int
+	FinalURL   string
+}
+
+// Fetcher downloads a single page, scrapers and url collectors only depend on this
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (Page, error)
+	Close() error
+}
+
+// StatusError is returned for pages that loaded with a 4xx/5xx status
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.URL, e.StatusCode)
+}
+
+type retryFetcher struct {
+	Fetcher
+	attempts int
+	wait     time.Duration
+}
+
+// WithRetries retries failed fetches up to attempts times in total, waiting between tries.
+// 4xx responses are not retried, they won't get better.
+func WithRetries(f Fetcher, attempts int, wait time.Duration) Fetcher {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &retryFetcher{Fetcher: f, attempts: attempts, wait: wait}
+}
+
+func (r *retryFetcher) Fetch(ctx context.Context, url string) (Page, error) {
+	var lastErr error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		page, err := r.Fetcher.Fetch(ctx, url)
+		if err == nil {
+			return page, nil
+		}
+		lastErr = err
+
+		var statusErr *StatusError
+		if ctx.Err() != nil || (errors.As(err, &statusErr) && statusErr.StatusCode < 500) {
+			return page, err
+		}
+		if attempt < r.attempts {
+			log.Printf("Fetch attempt %d/%d for %s failed: %v", attempt, r.attempts, url, err)
+			select {
+			case <-ctx.Done():
+				return Page{}, ctx.Err()
+			case <-time.After(r.wait):
+			}
+		}
+	}
+	return Page{}, fmt.Errorf("fetch failed %d times: %w", r.attempts, lastErr)
+}
+
+// New builds the fetcher configured for a source, paced by limit and wrapped with its retry policy.
+// captchaMarkers are the challenge page texts of the source's site. A nil pool fetches directly.
+func New(ctx context.Context, browser config.Browser, src config.Source, limit config.RateLimit, captchaMarkers []string, pool *Pool) Fetcher {
+	var f Fetcher
+	switch {
+	case src.Fetcher == config.FetcherHTTP && pool != nil:
+		f = NewProxiedHTTP(pool, browser.UserAgent)
+	case src.Fetcher == config.FetcherHTTP:
+		f = NewHTTP(nil, browser.UserAgent)
+	default:
+		f = NewChrome(ctx, browser, src.DataDir, pool)
+	}
+	// every retry waits for the limiter too
+	return WithRetries(WithRateLimit(f, limit, captchaMarkers), src.Retries, time.Second)
+}
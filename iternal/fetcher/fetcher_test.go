@@ -0,0 +1,243 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/offer", http.StatusMovedPermanently)
+		case "/offer":
+			assert.Equal(t, "test-agent", r.UserAgent())
+			w.Write([]byte("<html><body><h1>Go dev</h1></body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	f := NewHTTP(srv.Client(), "test-agent")
+	defer f.Close()
+
+	page, err := f.Fetch(context.Background(), srv.URL+"/old")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+	assert.Equal(t, srv.URL+"/offer", page.FinalURL)
+	assert.Contains(t, page.HTML, "Go dev")
+
+	page, err = f.Fetch(context.Background(), srv.URL+"/gone")
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, http.StatusNotFound, page.StatusCode)
+}
+
+func TestWithRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte("ok"))
+		case "/missing":
+			calls.Add(1)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	f := WithRetries(NewHTTP(srv.Client(), ""), 3, time.Millisecond)
+
+	page, err := f.Fetch(context.Background(), srv.URL+"/flaky")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", page.HTML)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	_, err = f.Fetch(context.Background(), srv.URL+"/missing")
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, int32(1), calls.Load(), "4xx should not be retried")
+}
+
+func TestLimiterSpacing(t *testing.T) {
+	l := NewLimiter(config.RateLimit{MinDelay: 20 * time.Millisecond, MaxDelay: 40 * time.Millisecond, MaxBackoff: 200 * time.Millisecond})
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, l.Wait(ctx))
+	assert.Less(t, time.Since(start), 20*time.Millisecond, "first request goes out at once")
+	require.NoError(t, l.Wait(ctx))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "40ms spacing with 25% jitter")
+
+	assert.Equal(t, 200*time.Millisecond, l.Backoff(), "capped at max_backoff")
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(waitCtx), context.DeadlineExceeded)
+
+	for i := 0; i < 50; i++ {
+		l.Healthy()
+	}
+	assert.Equal(t, 20*time.Millisecond, l.Interval(), "never faster than min_delay")
+
+	// zero delays never wait, tests and local runs rely on it
+	l = NewLimiter(config.RateLimit{})
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Wait(ctx))
+	}
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestWithRateLimitBacksOff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	limit := config.RateLimit{MaxDelay: time.Millisecond, MaxBackoff: time.Minute}
+	f := WithRateLimit(NewHTTP(srv.Client(), ""), limit, []string{"Verifying you are human"})
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	limiter := LimiterFor(u.Host, limit)
+
+	_, err = f.Fetch(context.Background(), srv.URL+"/busy")
+	require.Error(t, err)
+	assert.Equal(t, time.Second, limiter.Interval(), "429 backs off")
+
+	limiter.Healthy()
+	assert.Equal(t, 900*time.Millisecond, limiter.Interval())
+	limiter.Backoff()
+	assert.Equal(t, 1800*time.Millisecond, limiter.Interval())
+
+	// the next fetch would wait the backed off spacing
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = f.Fetch(ctx, srv.URL+"/ok")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	markers := []string{"Verifying you are human", "Just a moment"}
+	assert.True(t, throttled(Page{HTML: "<p>Verifying you are human. This may take a few seconds.</p>"}, nil, markers))
+	assert.True(t, throttled(Page{HTML: "<title>Just a moment...</title>"}, nil, markers), "any marker of the site")
+	assert.False(t, throttled(Page{HTML: "<p>Verifying you are human.</p>"}, nil, nil), "a site without markers")
+	assert.False(t, throttled(Page{}, &StatusError{StatusCode: http.StatusNotFound}, markers))
+}
+
+// proxyStandIn answers proxied requests itself, the way a forward proxy would relay them
+func proxyStandIn(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		assert.True(t, r.URL.IsAbs(), "proxies get the absolute url")
+		w.Write([]byte("via proxy " + r.URL.String()))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProxiedHTTPRotatesAndEvicts(t *testing.T) {
+	var hits atomic.Int32
+	good := proxyStandIn(t, &hits)
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	pool, err := NewPool(config.Proxies{
+		URLs:        []string{good.URL, dead.URL},
+		Rotate:      config.RotatePerRequest,
+		MaxFailures: 1,
+		Cooldown:    time.Minute,
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+	f := NewProxiedHTTP(pool, "")
+
+	page, err := f.Fetch(context.Background(), "http://jobs.example/offer/1")
+	require.NoError(t, err)
+	assert.Equal(t, "via proxy http://jobs.example/offer/1", page.HTML)
+
+	// the dead proxy is next in line, one failure evicts it
+	_, err = f.Fetch(context.Background(), "http://jobs.example/offer/2")
+	require.Error(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = f.Fetch(context.Background(), "http://jobs.example/offer/3")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(4), hits.Load())
+
+	stats := pool.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(4), stats[0].Successes)
+	assert.Equal(t, 1.0, stats[0].SuccessRate())
+	assert.Equal(t, int64(1), stats[1].Failures)
+	assert.Equal(t, 0.0, stats[1].SuccessRate())
+	assert.Equal(t, now.Add(time.Minute), stats[1].EvictedUntil)
+
+	// back in rotation after the cooldown
+	now = now.Add(time.Minute)
+	first, err := pool.Next()
+	require.NoError(t, err)
+	second, err := pool.Next()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestPoolSessionRotation(t *testing.T) {
+	pool, err := NewPool(config.Proxies{
+		URLs:        []string{"http://one:8080", "socks5://two:1080"},
+		Rotate:      config.RotatePerSession,
+		MaxFailures: 2,
+		Cooldown:    time.Minute,
+	})
+	require.NoError(t, err)
+
+	proxy, err := pool.pick(nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "one:8080", proxy.Host)
+	same, err := pool.pick(proxy, false)
+	require.NoError(t, err)
+	assert.Same(t, proxy, same, "a session keeps its proxy")
+
+	pool.Report(proxy, false)
+	assert.True(t, pool.Usable(proxy), "evicted only after max_failures in a row")
+	pool.Report(proxy, false)
+	assert.False(t, pool.Usable(proxy))
+	next, err := pool.pick(proxy, false)
+	require.NoError(t, err)
+	assert.Equal(t, "two:1080", next.Host)
+
+	pool.Report(next, false)
+	pool.Report(next, false)
+	_, err = pool.Next()
+	assert.ErrorIs(t, err, ErrNoProxy)
+
+	assert.True(t, proxyFailed(context.Background(), &StatusError{StatusCode: http.StatusProxyAuthRequired}))
+	assert.False(t, proxyFailed(context.Background(), &StatusError{StatusCode: http.StatusNotFound}))
+
+	none, err := NewPool(config.Proxies{})
+	require.NoError(t, err)
+	assert.Nil(t, none)
+	proxy, err = none.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, proxy)
+}
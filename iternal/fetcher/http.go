@@ -0,0 +1,91 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTP fetches pages with a plain http client, for sources that render without javascript
+type HTTP struct {
+	client    *http.Client
+	userAgent string
+	pool      *Pool
+	proxy     *url.URL
+}
+
+// NewHTTP uses client when given, otherwise a client with a 30s timeout
+func NewHTTP(client *http.Client, userAgent string) *HTTP {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTP{client: client, userAgent: userAgent}
+}
+
+type proxyKey struct{}
+
+// NewProxiedHTTP sends every request through a proxy from pool, rotated as the pool is configured
+func NewProxiedHTTP(pool *Pool, userAgent string) *HTTP {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the proxy picked for a fetch rides in the request context
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, _ := req.Context().Value(proxyKey{}).(*url.URL)
+		return proxy, nil
+	}
+	client := &http.Client{Timeout: 30 * time.Second, Transport: transport}
+	return &HTTP{client: client, userAgent: userAgent, pool: pool}
+}
+
+func (h *HTTP) Fetch(ctx context.Context, url string) (Page, error) {
+	if h.pool == nil {
+		return h.fetch(ctx, url)
+	}
+	proxy, err := h.pool.pick(h.proxy, false)
+	if err != nil {
+		return Page{}, err
+	}
+	h.proxy = proxy
+	page, err := h.fetch(context.WithValue(ctx, proxyKey{}, proxy), url)
+	h.pool.Report(proxy, !proxyFailed(ctx, err))
+	return page, err
+}
+
+func (h *HTTP) fetch(ctx context.Context, url string) (Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	if h.userAgent != "" {
+		req.Header.Set("User-Agent", h.userAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("Accept-Language", "pl-PL,pl;q=0.9,en;q=0.8")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{
+		HTML:       string(body),
+		StatusCode: resp.StatusCode,
+		FinalURL:   resp.Request.URL.String(),
+	}
+	if resp.StatusCode >= 400 {
+		return page, &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	return page, nil
+}
+
+func (h *HTTP) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+)
+
+// Limiter is a token bucket spacing requests to one host. The spacing is jittered by ±25%,
+// doubles (up to MaxBackoff) when the site pushes back and shrinks by 10% per healthy
+// response until it reaches MinDelay again.
+type Limiter struct {
+	mu       sync.Mutex
+	limit    config.RateLimit
+	interval time.Duration
+	tokens   float64
+	updated  time.Time
+}
+
+func NewLimiter(limit config.RateLimit) *Limiter {
+	limit.Burst = max(limit.Burst, 1)
+	return &Limiter{limit: limit, interval: limit.MaxDelay, tokens: float64(limit.Burst)}
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*Limiter{}
+)
+
+// LimiterFor returns the limiter shared by everything talking to host in this process,
+// the first limit given for a host wins
+func LimiterFor(host string, limit config.RateLimit) *Limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[host]
+	if !ok {
+		l = NewLimiter(limit)
+		limiters[host] = l
+	}
+	return l
+}
+
+// Wait blocks until the next request may go out, it returns early with ctx's error
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	gap := time.Duration(float64(l.interval) * (0.75 + rand.Float64()/2))
+	now := time.Now()
+	if gap <= 0 {
+		l.tokens = float64(l.limit.Burst)
+	} else if !l.updated.IsZero() {
+		l.tokens = math.Min(float64(l.limit.Burst), l.tokens+float64(now.Sub(l.updated))/float64(gap))
+	}
+	l.updated = now
+	// reserve a token, a negative balance is the time left until it refills
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens * float64(gap))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Backoff doubles the spacing and drops any saved burst, returns the new spacing
+func (l *Limiter) Backoff() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit.MaxBackoff <= 0 {
+		return l.interval
+	}
+	l.interval = min(l.limit.MaxBackoff, max(2*l.interval, time.Second))
+	l.tokens = math.Min(l.tokens, 0)
+	return l.interval
+}
+
+// Healthy shrinks the spacing toward MinDelay
+func (l *Limiter) Healthy() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = max(l.limit.MinDelay, l.interval*9/10)
+}
+
+// Interval is the current spacing before jitter
+func (l *Limiter) Interval() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.interval
+}
+
+type limitedFetcher struct {
+	Fetcher
+	limit          config.RateLimit
+	captchaMarkers []string
+}
+
+// WithRateLimit paces fetches through the limiter of each url's host,
+// a page containing any of captchaMarkers backs off like a 429
+func WithRateLimit(f Fetcher, limit config.RateLimit, captchaMarkers []string) Fetcher {
+	return &limitedFetcher{Fetcher: f, limit: limit, captchaMarkers: captchaMarkers}
+}
+
+func (l *limitedFetcher) Fetch(ctx context.Context, rawURL string) (Page, error) {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Host
+	}
+	limiter := LimiterFor(host, l.limit)
+	if err := limiter.Wait(ctx); err != nil {
+		return Page{}, err
+	}
+
+	page, err := l.Fetcher.Fetch(ctx, rawURL)
+	switch {
+	case throttled(page, err, l.captchaMarkers):
+		log.Printf("%s is pushing back, slowing down to one request per %s", host, limiter.Backoff())
+	case err == nil:
+		limiter.Healthy()
+	}
+	return page, err
+}
+
+// 429 and 503 are rate limits, 403 and the site's challenge pages are bot detection
+func throttled(page Page, err error, captchaMarkers []string) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusForbidden, http.StatusServiceUnavailable:
+			return true
+		}
+	}
+	for _, marker := range captchaMarkers {
+		if strings.Contains(page.HTML, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+)
+
+// ErrNoProxy is returned while every proxy of the pool sits out its cooldown
+var ErrNoProxy = errors.New("every proxy is cooling down")
+
+// ProxyStats is the running record of one proxy
+type ProxyStats struct {
+	URL          string
+	Successes    int64
+	Failures     int64
+	EvictedUntil time.Time
+}
+
+// SuccessRate is the share of successful fetches, 0 before the first one
+func (s ProxyStats) SuccessRate() float64 {
+	if s.Successes+s.Failures == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(s.Successes+s.Failures)
+}
+
+type proxyState struct {
+	url         *url.URL
+	stats       ProxyStats
+	consecutive int
+}
+
+// Pool hands out proxies round robin and evicts the ones failing MaxFailures times in a row
+// for Cooldown. A nil or empty pool means going direct.
+type Pool struct {
+	mu          sync.Mutex
+	proxies     []*proxyState
+	next        int
+	perRequest  bool
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+}
+
+// NewPool parses the configured proxy urls, returns nil when there are none
+func NewPool(cfg config.Proxies) (*Pool, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, nil
+	}
+	p := &Pool{
+		perRequest:  cfg.Rotate != config.RotatePerSession,
+		maxFailures: max(cfg.MaxFailures, 1),
+		cooldown:    cfg.Cooldown,
+		now:         time.Now,
+	}
+	for _, raw := range cfg.URLs {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %q: %w", raw, err)
+		}
+		p.proxies = append(p.proxies, &proxyState{url: u, stats: ProxyStats{URL: u.Redacted()}})
+	}
+	return p, nil
+}
+
+// Next returns the next proxy that is not cooling down, nil without a pool
+func (p *Pool) Next() (*url.URL, error) {
+	if p == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for range p.proxies {
+		s := p.proxies[p.next%len(p.proxies)]
+		p.next++
+		if !now.Before(s.stats.EvictedUntil) {
+			return s.url, nil
+		}
+	}
+	return nil, ErrNoProxy
+}
+
+// Usable tells whether proxy may still be used, session fetchers switch when it is not
+func (p *Pool) Usable(proxy *url.URL) bool {
+	if p == nil || proxy == nil {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.state(proxy)
+	return s == nil || !p.now().Before(s.stats.EvictedUntil)
+}
+
+// Report records the outcome of a fetch through proxy
+func (p *Pool) Report(proxy *url.URL, ok bool) {
+	if p == nil || proxy == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.state(proxy)
+	if s == nil {
+		return
+	}
+	if ok {
+		s.stats.Successes++
+		s.consecutive = 0
+		return
+	}
+	s.stats.Failures++
+	s.consecutive++
+	if s.consecutive >= p.maxFailures {
+		s.consecutive = 0
+		s.stats.EvictedUntil = p.now().Add(p.cooldown)
+		log.Printf("Proxy %s failed %d times in a row, evicted for %s", s.stats.URL, p.maxFailures, p.cooldown)
+	}
+}
+
+// Stats returns a snapshot of every proxy in config order
+func (p *Pool) Stats() []ProxyStats {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]ProxyStats, len(p.proxies))
+	for i, s := range p.proxies {
+		stats[i] = s.stats
+	}
+	return stats
+}
+
+// LogStats prints the success rate of every proxy
+func (p *Pool) LogStats() {
+	for _, s := range p.Stats() {
+		log.Printf("Proxy %s: %d ok, %d failed, %.0f%% success", s.URL, s.Successes, s.Failures, 100*s.SuccessRate())
+	}
+}
+
+func (p *Pool) state(proxy *url.URL) *proxyState {
+	for _, s := range p.proxies {
+		if s.url == proxy {
+			return s
+		}
+	}
+	return nil
+}
+
+// picks the proxy for the next fetch, session rotation keeps current until it gets evicted
+func (p *Pool) pick(current *url.URL, perSession bool) (*url.URL, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if (perSession || !p.perRequest) && current != nil && p.Usable(current) {
+		return current, nil
+	}
+	return p.Next()
+}
+
+// 407 is the proxy itself refusing, 403 and 429 usually mean the board banned its address,
+// a fetch cancelled by the caller says nothing about the proxy
+func proxyFailed(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusProxyAuthRequired, http.StatusForbidden, http.StatusTooManyRequests:
+			return true
+		}
+		return false
+	}
+	return true
+}
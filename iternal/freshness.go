@@ -0,0 +1,50 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+)
+
+// SelectStale drops urls of offers scraped less than rescrapeAfter ago and orders the rest,
+// never scraped urls first in their original order, then the least recently scraped.
+// rescrapeAfter 0 keeps every url as it is.
+func SelectStale(ctx context.Context, db *sql.DB, source string, urls []string, rescrapeAfter time.Duration) ([]string, error) {
+	if rescrapeAfter <= 0 {
+		return urls, nil
+	}
+	states, err := database.New(db).ListJobOfferStatesBySource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	lastSeen := make(map[string]time.Time, len(states))
+	for _, state := range states {
+		if state.LastSeenAt.Valid {
+			lastSeen[state.Url] = state.LastSeenAt.Time
+		}
+	}
+
+	type candidate struct {
+		url      string
+		lastSeen time.Time
+	}
+	var stale []candidate
+	for _, url := range urls {
+		seen := lastSeen[urlNormalizer(url)]
+		if !seen.IsZero() && time.Since(seen) < rescrapeAfter {
+			continue
+		}
+		stale = append(stale, candidate{url, seen})
+	}
+	// zero time sorts first, never seen urls keep the order of the url file
+	sort.SliceStable(stale, func(i, j int) bool { return stale[i].lastSeen.Before(stale[j].lastSeen) })
+
+	selected := make([]string, len(stale))
+	for i, c := range stale {
+		selected[i] = c.url
+	}
+	return selected, nil
+}
@@ -0,0 +1,41 @@
+package iternal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectStale(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	fresh := "https://nofluffjobs.com/pl/job/fresh"
+	week := "https://nofluffjobs.com/pl/job/week"
+	month := "https://nofluffjobs.com/pl/job/month"
+	for _, url := range []string{fresh, week, month} {
+		require.NoError(t, saveJobOffer(ctx, db, scraper.JobOffer{Title: "Go", URL: url, Source: "nofluffjobs.com"}, nil))
+	}
+	_, err := db.Exec(`UPDATE job_offers SET last_seen_at = datetime('now', '-7 days') WHERE url = ?`, week)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE job_offers SET last_seen_at = datetime('now', '-30 days') WHERE url = ?`, month)
+	require.NoError(t, err)
+
+	urls := []string{fresh + "?utm_source=x", week, "https://nofluffjobs.com/pl/job/new-1", month, "https://nofluffjobs.com/pl/job/new-2"}
+
+	got, err := SelectStale(ctx, db, "nofluffjobs.com", urls, 72*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://nofluffjobs.com/pl/job/new-1", "https://nofluffjobs.com/pl/job/new-2", month, week}, got)
+
+	// other sources don't count as seen
+	got, err = SelectStale(ctx, db, "justjoin.it", urls, 72*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, urls, got)
+
+	got, err = SelectStale(ctx, db, "nofluffjobs.com", urls, 0)
+	require.NoError(t, err)
+	assert.Equal(t, urls, got, "policy off keeps every url")
+}
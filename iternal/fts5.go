@@ -0,0 +1,6 @@
+//go:build sqlite_fts5
+
+package iternal
+
+// go-sqlite3 only compiles fts5 in with this tag
+const ftsEnabled = true
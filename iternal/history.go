@@ -0,0 +1,111 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+)
+
+// FieldChange is one field of an offer changed by a rescrape, empty values were not set
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// OfferVersion groups the fields changed by one rescrape
+type OfferVersion struct {
+	Version   int64         `json:"version"`
+	ChangedAt time.Time     `json:"changed_at"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// fields compared on rescrape, dates are left out, last_seen_at changes every time
+func diffJobOffer(stored database.JobOffer, next database.UpsertJobOfferParams) []FieldChange {
+	fields := []struct {
+		name          string
+		before, after sql.NullString
+	}{
+		{"title", sql.NullString{String: stored.Title, Valid: true}, sql.NullString{String: next.Title, Valid: true}},
+		{"company", stored.Company, next.Company},
+		{"location", stored.Location, next.Location},
+		{"description", stored.Description, next.Description},
+		{"skills", stored.Skills, next.Skills},
+		{"salary_employment", stored.SalaryEmployment, next.SalaryEmployment},
+		{"salary_b2b", stored.SalaryB2b, next.SalaryB2b},
+		{"salary_contract", stored.SalaryContract, next.SalaryContract},
+		{"work_mode", stored.WorkMode, next.WorkMode},
+		{"country", stored.Country, next.Country},
+	}
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if f.before.String != f.after.String {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.before.String, New: f.after.String})
+		}
+	}
+	return changes
+}
+
+// stores the changes as the next version of the offer
+func saveVersion(ctx context.Context, q *database.Queries, offerID string, changes []FieldChange) error {
+	latest, err := q.GetLatestJobOfferVersion(ctx, offerID)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		params := database.InsertJobOfferVersionParams{
+			JobOfferID: offerID,
+			Version:    latest + 1,
+			Field:      c.Field,
+			OldValue:   sql.NullString{String: c.Old, Valid: c.Old != ""},
+			NewValue:   sql.NullString{String: c.New, Valid: c.New != ""},
+		}
+		if err := q.InsertJobOfferVersion(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OfferHistory returns the recorded changes of an offer oldest first, empty for offers never changed
+func OfferHistory(ctx context.Context, db *sql.DB, id string) ([]OfferVersion, error) {
+	q := database.New(db)
+	if _, err := q.GetJobOffer(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no offer with id %q", id)
+		}
+		return nil, err
+	}
+	rows, err := q.ListJobOfferVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return groupVersions(rows), nil
+}
+
+// rows come ordered by version
+func groupVersions(rows []database.JobOfferVersion) []OfferVersion {
+	versions := []OfferVersion{}
+	for _, row := range rows {
+		if len(versions) == 0 || versions[len(versions)-1].Version != row.Version {
+			versions = append(versions, OfferVersion{Version: row.Version, ChangedAt: row.ChangedAt})
+		}
+		v := &versions[len(versions)-1]
+		v.Changes = append(v.Changes, FieldChange{Field: row.Field, Old: row.OldValue.String, New: row.NewValue.String})
+	}
+	return versions
+}
+
+// OfferIDByURL finds the stored offer for a scraped url
+func OfferIDByURL(ctx context.Context, db *sql.DB, url string) (string, error) {
+	row, err := database.New(db).GetJobOfferByUrl(ctx, urlNormalizer(url))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("no offer with url %q", url)
+	}
+	return row.ID, err
+}
@@ -0,0 +1,51 @@
+package iternal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveJobOfferRecordsVersions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	job := scraper.JobOffer{
+		Title:     "Go Developer",
+		URL:       "https://justjoin.it/job-offer/go?utm=x",
+		Source:    "justjoin.it",
+		Skills:    []string{"Go"},
+		SalaryB2B: "20 000 - 25 000 PLN net/month",
+	}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	id, err := OfferIDByURL(ctx, db, job.URL)
+	require.NoError(t, err)
+
+	// identical rescrape records nothing
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	history, err := OfferHistory(ctx, db, id)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	job.SalaryB2B = "22 000 - 27 000 PLN net/month"
+	job.Skills = []string{"Go", "Kubernetes"}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	job.Title = "Senior Go Developer"
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+
+	history, err = OfferHistory(ctx, db, id)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.EqualValues(t, 1, history[0].Version)
+	assert.Equal(t, []FieldChange{
+		{Field: "salary_b2b", Old: "20 000 - 25 000 PLN net/month", New: "22 000 - 27 000 PLN net/month"},
+		{Field: "skills", Old: `["Go"]`, New: `["Go","Kubernetes"]`},
+	}, history[0].Changes)
+	assert.Equal(t, []FieldChange{{Field: "title", Old: "Go Developer", New: "Senior Go Developer"}}, history[1].Changes)
+
+	_, err = OfferHistory(ctx, db, "nope")
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+// Package migrate applies the goose migrations of sql/schema without the goose binary. Versions are
+// kept in goose's own goose_db_version table, so databases migrated by hand with goose carry on.
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Migration is one sql/schema file
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// creates an fts5 table, skipped while the sqlite driver is built without fts5
+	NeedsFTS bool
+}
+
+// State is a migration and whether the database has it
+type State struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// why Up left the migration out, empty when it was applied or is waiting
+	Skipped string
+}
+
+// Set is every migration of the schema, oldest first
+type Set []Migration
+
+// ErrNewerSchema is returned for a database migrated by a newer binary than this one
+var ErrNewerSchema = errors.New("database schema is newer than this binary")
+
+var fileNameRe = regexp.MustCompile(`^(\d+)_\w+\.sql$`)
+
+// Load reads and validates the *.sql migrations in the root of fsys: numbered file names,
+// unique versions, an Up and a Down section each and balanced StatementBegin/End
+func Load(fsys fs.FS) (Set, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	var set Set
+	versions := map[int64]string{}
+	for _, name := range names {
+		m := fileNameRe.FindStringSubmatch(name)
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<description>.sql", name)
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: bad version %q", name, m[1])
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		versions[version] = name
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := parse(name, version, string(content))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", name, err)
+		}
+		set = append(set, migration)
+	}
+	slices.SortFunc(set, func(a, b Migration) int { return int(a.Version - b.Version) })
+	return set, nil
+}
+
+// splits a file into its goose Up and Down sections
+func parse(name string, version int64, content string) (Migration, error) {
+	m := Migration{Version: version, Name: name}
+	var up, down strings.Builder
+	var section *strings.Builder
+	seenUp, seenDown, inStatement := false, false, false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		directive, ok := strings.CutPrefix(strings.TrimSpace(text), "-- +goose ")
+		if !ok {
+			if section == nil {
+				if t := strings.TrimSpace(text); t != "" && !strings.HasPrefix(t, "--") {
+					return m, fmt.Errorf("line %d: sql before -- +goose Up", line)
+				}
+				continue
+			}
+			section.WriteString(text)
+			section.WriteByte('\n')
+			continue
+		}
+		switch strings.TrimSpace(directive) {
+		case "Up":
+			if seenUp || seenDown {
+				return m, fmt.Errorf("line %d: unexpected -- +goose Up", line)
+			}
+			seenUp, section = true, &up
+		case "Down":
+			if !seenUp || seenDown || inStatement {
+				return m, fmt.Errorf("line %d: unexpected -- +goose Down", line)
+			}
+			seenDown, section = true, &down
+		case "StatementBegin":
+			if section == nil || inStatement {
+				return m, fmt.Errorf("line %d: unexpected -- +goose StatementBegin", line)
+			}
+			inStatement = true
+		case "StatementEnd":
+			if !inStatement {
+				return m, fmt.Errorf("line %d: -- +goose StatementEnd without StatementBegin", line)
+			}
+			inStatement = false
+		default:
+			return m, fmt.Errorf("line %d: unsupported -- +goose %s", line, strings.TrimSpace(directive))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return m, err
+	}
+
+	switch {
+	case inStatement:
+		return m, errors.New("-- +goose StatementBegin without StatementEnd")
+	case !seenUp || strings.TrimSpace(up.String()) == "":
+		return m, errors.New("no -- +goose Up section")
+	case !seenDown || strings.TrimSpace(down.String()) == "":
+		return m, errors.New("no -- +goose Down section, every migration must be reversible")
+	}
+	m.Up, m.Down = up.String(), down.String()
+	m.NeedsFTS = strings.Contains(strings.ToLower(m.Up), "using fts5")
+	return m, nil
+}
+
+// the table goose creates on its first run, version 0 marks it as initialized
+const createVersionTable = `CREATE TABLE goose_db_version (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    version_id INTEGER NOT NULL,
+    is_applied INTEGER NOT NULL,
+    tstamp TIMESTAMP DEFAULT (datetime('now'))
+)`
+
+func ensureVersionTable(ctx context.Context, db *sql.DB) error {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`).Scan(&n)
+	if err != nil || n > 0 {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, createVersionTable); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO goose_db_version (version_id, is_applied) VALUES (0, 1)`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied versions and when, the last row of a version wins since old goose logged downs as is_applied = 0
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int64]time.Time, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version_id, is_applied, tstamp FROM goose_db_version WHERE version_id > 0 ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var at sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &at); err != nil {
+			return nil, err
+		}
+		if isApplied {
+			applied[version] = at.Time
+		} else {
+			delete(applied, version)
+		}
+	}
+	return applied, rows.Err()
+}
+
+// fails for versions this set doesn't have, a newer binary migrated the database
+func (s Set) checkKnown(applied map[int64]time.Time) error {
+	var unknown []int64
+	for version := range applied {
+		if !slices.ContainsFunc(s, func(m Migration) bool { return m.Version == version }) {
+			unknown = append(unknown, version)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	return fmt.Errorf("%w: it has migration %d, this binary knows up to %d", ErrNewerSchema, unknown[len(unknown)-1], s.latest())
+}
+
+func (s Set) latest() int64 {
+	if len(s) == 0 {
+		return 0
+	}
+	return s[len(s)-1].Version
+}
+
+func ftsAvailable(ctx context.Context, db *sql.DB) (bool, error) {
+	var used bool
+	err := db.QueryRowContext(ctx, `SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&used)
+	return used, err
+}
+
+// Status lists every migration with whether db has it
+func (s Set) Status(ctx context.Context, db *sql.DB) ([]State, error) {
+	if err := ensureVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	fts, err := ftsAvailable(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, 0, len(s))
+	for _, m := range s {
+		at, ok := applied[m.Version]
+		state := State{Migration: m, Applied: ok, AppliedAt: at}
+		if !ok && m.NeedsFTS && !fts {
+			state.Skipped = "needs sqlite built with fts5"
+		}
+		states = append(states, state)
+	}
+	return states, s.checkKnown(applied)
+}
+
+// Pending returns the migrations Up would apply
+func (s Set) Pending(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	states, err := s.Status(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, st := range states {
+		if !st.Applied && st.Skipped == "" {
+			pending = append(pending, st.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every missing migration oldest first, each in its own transaction. A migration
+// skipped before (fts5 on a driver without it) is applied by the first binary that can,
+// even after newer ones. Returns the applied migrations.
+func (s Set) Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	pending, err := s.Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range pending {
+		if err := apply(ctx, db, m.Up, `INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, 1)`, m.Version); err != nil {
+			return pending[:i], fmt.Errorf("migration %s up: %w", m.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down reverts the newest applied migration, nil when there is none
+func (s Set) Down(ctx context.Context, db *sql.DB) (*Migration, error) {
+	states, err := s.Status(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(states) - 1; i >= 0; i-- {
+		if !states[i].Applied {
+			continue
+		}
+		m := states[i].Migration
+		if err := apply(ctx, db, m.Down, `DELETE FROM goose_db_version WHERE version_id = ?`, m.Version); err != nil {
+			return nil, fmt.Errorf("migration %s down: %w", m.Name, err)
+		}
+		return &m, nil
+	}
+	return nil, nil
+}
+
+// runs a migration section and its version bookkeeping in one transaction, sqlite rolls ddl back too
+func apply(ctx context.Context, db *sql.DB, section, record string, version int64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, section); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Validate runs every migration up, down and up again on an empty in-memory database,
+// so a Down that can't run fails here instead of on a database with data in it
+func (s Set) Validate(ctx context.Context) error {
+	db, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	// every connection to :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+
+	if _, err := s.Up(ctx, db); err != nil {
+		return err
+	}
+	for {
+		m, err := s.Down(ctx, db)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			break
+		}
+	}
+	// leftovers of a Down that missed something break the next Up
+	if _, err := s.Up(ctx, db); err != nil {
+		return fmt.Errorf("after reverting every migration: %w", err)
+	}
+	return nil
+}
+
+// String is the file name without its extension, "016_create_companies"
+func (m Migration) String() string {
+	return strings.TrimSuffix(m.Name, path.Ext(m.Name))
+}
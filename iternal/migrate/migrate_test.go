@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pfczx/jobscraper/sql/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRoundTrip(t *testing.T) {
+	set, err := Load(schema.FS)
+	require.NoError(t, err)
+	require.NoError(t, set.Validate(context.Background()))
+}
+
+func file(sql string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(sql)} }
+
+func TestValidateCatchesBrokenDown(t *testing.T) {
+	set, err := Load(fstest.MapFS{
+		"001_create_jobs.sql": file("-- +goose Up\nCREATE TABLE job_offers (id TEXT PRIMARY KEY);\n\n-- +goose Down\nDROP TABLE job_offers;\n"),
+		// sqlite takes no type in DROP COLUMN
+		"003_alter_table_addedEmbCol.sql": file("-- +goose Up\nALTER TABLE job_offers ADD COLUMN embedding BLOB;\n\n-- +goose Down\nALTER TABLE job_offers DROP COLUMN embedding BLOB;\n"),
+	})
+	require.NoError(t, err)
+	err = set.Validate(context.Background())
+	assert.ErrorContains(t, err, "003_alter_table_addedEmbCol.sql down")
+}
+
+func TestLoadRejectsBadFiles(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"no down":          {"001_a.sql": file("-- +goose Up\nCREATE TABLE a (id INTEGER);\n")},
+		"empty up":         {"001_a.sql": file("-- +goose Up\n-- +goose Down\nDROP TABLE a;\n")},
+		"unclosed block":   {"001_a.sql": file("-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+		"duplicate number": {"001_a.sql": file("-- +goose Up\nSELECT 1;\n-- +goose Down\nSELECT 1;\n"), "01_b.sql": file("-- +goose Up\nSELECT 1;\n-- +goose Down\nSELECT 1;\n")},
+		"unnumbered":       {"create_a.sql": file("-- +goose Up\nSELECT 1;\n-- +goose Down\nSELECT 1;\n")},
+	} {
+		_, err := Load(fsys)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "jobs.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	set, err := Load(fstest.MapFS{
+		"001_create_jobs.sql":  file("-- +goose Up\nCREATE TABLE job_offers (id TEXT PRIMARY KEY);\n\n-- +goose Down\nDROP TABLE job_offers;\n"),
+		"002_alter_title.sql":  file("-- +goose Up\nALTER TABLE job_offers ADD COLUMN title TEXT;\n\n-- +goose Down\nALTER TABLE job_offers DROP COLUMN title;\n"),
+		"003_create_fts.sql":   file("-- +goose Up\nCREATE VIRTUAL TABLE job_offers_fts USING fts5(title);\n\n-- +goose Down\nDROP TABLE IF EXISTS job_offers_fts;\n"),
+		"004_alter_salary.sql": file("-- +goose Up\nALTER TABLE job_offers ADD COLUMN salary TEXT;\n\n-- +goose Down\nALTER TABLE job_offers DROP COLUMN salary;\n"),
+	})
+	require.NoError(t, err)
+	fts, err := ftsAvailable(ctx, db)
+	require.NoError(t, err)
+
+	applied, err := set.Up(ctx, db)
+	require.NoError(t, err)
+	if fts {
+		assert.Len(t, applied, 4)
+	} else {
+		assert.Len(t, applied, 3, "fts5 waits for a driver built with it")
+	}
+	_, err = db.Exec(`INSERT INTO job_offers (id, title, salary) VALUES ('1', 'Go Developer', '10 000 PLN')`)
+	require.NoError(t, err)
+
+	applied, err = set.Up(ctx, db)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "an up to date database is left alone")
+
+	reverted, err := set.Down(ctx, db)
+	require.NoError(t, err)
+	require.NotNil(t, reverted)
+	assert.Equal(t, int64(4), reverted.Version)
+	states, err := set.Status(ctx, db)
+	require.NoError(t, err)
+	assert.True(t, states[1].Applied)
+	assert.False(t, states[3].Applied)
+	assert.Equal(t, !fts, states[2].Skipped != "")
+
+	// a migration of a newer binary stops this one from touching the database
+	_, err = db.Exec(`INSERT INTO goose_db_version (version_id, is_applied) VALUES (5, 1)`)
+	require.NoError(t, err)
+	_, err = set.Up(ctx, db)
+	assert.ErrorIs(t, err, ErrNewerSchema)
+}
@@ -0,0 +1,99 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// RunSummary is one recorded scrape run with the stats of every source it scraped
+type RunSummary struct {
+	ID         int64                 `json:"id"`
+	StartedAt  time.Time             `json:"started_at"`
+	FinishedAt time.Time             `json:"finished_at"`
+	Duration   time.Duration         `json:"duration_ns"`
+	Error      string                `json:"error,omitempty"`
+	Sources    []scraper.SourceStats `json:"sources"`
+}
+
+// SaveRunHistory records a finished scrape run, runErr is what the run ended with
+func SaveRunHistory(ctx context.Context, db *sql.DB, started, finished time.Time, sources []scraper.SourceStats, runErr error) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	querier := database.New(db).WithTx(tx)
+
+	params := database.InsertRunHistoryParams{
+		StartedAt:  started.UTC(),
+		FinishedAt: finished.UTC(),
+		DurationMs: finished.Sub(started).Milliseconds(),
+	}
+	if runErr != nil {
+		params.Error = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+	run, err := querier.InsertRunHistory(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range sources {
+		err := querier.InsertRunHistorySource(ctx, database.InsertRunHistorySourceParams{
+			RunID:       run.ID,
+			Source:      s.Source,
+			Attempted:   int64(s.Attempted),
+			Saved:       int64(s.Saved),
+			Closed:      int64(s.Closed),
+			FetchFailed: int64(s.FetchFailed),
+			ParseFailed: int64(s.ParseFailed),
+			SaveFailed:  int64(s.SaveFailed),
+			Captchas:    int64(s.Captchas),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return run.ID, tx.Commit()
+}
+
+// RecentRuns returns the last limit scrape runs, newest first
+func RecentRuns(ctx context.Context, db *sql.DB, limit int) ([]RunSummary, error) {
+	q := database.New(db)
+	runs, err := q.ListRunHistory(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]RunSummary, 0, len(runs))
+	for _, run := range runs {
+		rows, err := q.ListRunHistorySources(ctx, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		summary := RunSummary{
+			ID:         run.ID,
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			Duration:   time.Duration(run.DurationMs) * time.Millisecond,
+			Error:      run.Error.String,
+			Sources:    make([]scraper.SourceStats, 0, len(rows)),
+		}
+		for _, row := range rows {
+			summary.Sources = append(summary.Sources, scraper.SourceStats{
+				Source:      row.Source,
+				Attempted:   int(row.Attempted),
+				Saved:       int(row.Saved),
+				Closed:      int(row.Closed),
+				FetchFailed: int(row.FetchFailed),
+				ParseFailed: int(row.ParseFailed),
+				SaveFailed:  int(row.SaveFailed),
+				Captchas:    int(row.Captchas),
+			})
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
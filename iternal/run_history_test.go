@@ -0,0 +1,50 @@
+package iternal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	stats := scraper.NewStats("justjoin.it", "pracuj.pl")
+	stats.Attempt("pracuj.pl", "https://www.pracuj.pl/praca/1")
+	stats.Attempt("pracuj.pl", "https://www.pracuj.pl/praca/1")
+	stats.Attempt("pracuj.pl", "https://www.pracuj.pl/praca/2")
+	stats.Count("pracuj.pl", scraper.CaptchaHit)
+	stats.Count("pracuj.pl", scraper.Saved)
+	stats.Count("pracuj.pl", scraper.ParseFailed)
+
+	started := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	_, err := SaveRunHistory(ctx, db, started, started.Add(90*time.Second), stats.Sources(), nil)
+	require.NoError(t, err)
+	_, err = SaveRunHistory(ctx, db, started.Add(time.Hour), started.Add(time.Hour+time.Second), nil, errors.New("context canceled"))
+	require.NoError(t, err)
+
+	runs, err := RecentRuns(ctx, db, 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "context canceled", runs[0].Error, "newest first")
+	assert.Empty(t, runs[0].Sources)
+
+	first := runs[1]
+	assert.Empty(t, first.Error)
+	assert.True(t, started.Equal(first.StartedAt))
+	assert.Equal(t, 90*time.Second, first.Duration)
+	assert.Equal(t, []scraper.SourceStats{
+		{Source: "justjoin.it"},
+		{Source: "pracuj.pl", Attempted: 2, Saved: 1, ParseFailed: 1, Captchas: 1},
+	}, first.Sources)
+
+	runs, err = RecentRuns(ctx, db, 1)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
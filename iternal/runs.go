@@ -0,0 +1,202 @@
+package iternal
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+type RunKind string
+
+const (
+	RunCollect RunKind = "collect"
+	RunScrape  RunKind = "scrape"
+)
+
+// Run is the persisted progress of one collect-urls or scrape run of a source,
+// an interrupted run is picked up again by the next start with the same key
+type Run struct {
+	db      *sql.DB
+	q       *database.Queries
+	id      int64
+	resumed bool
+	page    int
+	urls    []string
+	done    map[string]bool
+	left    int
+}
+
+// Runs holds the scrape run of each source, keyed by the job_offers.source value
+type Runs map[string]*Run
+
+// a failed checkpoint only means the url gets scraped again next time
+func (r Runs) done(ctx context.Context, job scraper.JobOffer) {
+	run := r[job.Source]
+	if run == nil {
+		return
+	}
+	if err := run.Done(ctx, job.URL); err != nil {
+		log.Printf("Error %s in saving progress of %s", err, job.URL)
+	}
+}
+
+// StartScrapeRun resumes the unfinished scrape run of source when it was started for the
+// same listed urls, otherwise (or with restart) starts a new one scraping urls. listed is the
+// url list before the freshness and failure filters, which change as the run goes on.
+func StartScrapeRun(ctx context.Context, db *sql.DB, source string, listed, urls []string, restart bool) (*Run, error) {
+	sum := sha256.Sum256([]byte(strings.Join(listed, "\n")))
+	run, err := startRun(ctx, db, RunScrape, source, hex.EncodeToString(sum[:]), restart)
+	if err != nil {
+		return nil, err
+	}
+	if run.resumed && run.left == 0 {
+		// stopped between the last url and finishing
+		if err := run.Finish(ctx); err != nil {
+			return nil, err
+		}
+		return StartScrapeRun(ctx, db, source, listed, urls, false)
+	}
+	if !run.resumed {
+		if err := run.add(ctx, urls); err != nil {
+			return nil, err
+		}
+	}
+	return run, nil
+}
+
+// StartCollectRun resumes the unfinished collect run of source for the same start url,
+// otherwise (or with restart) starts a new one
+func StartCollectRun(ctx context.Context, db *sql.DB, source, startURL string, restart bool) (*Run, error) {
+	return startRun(ctx, db, RunCollect, source, startURL, restart)
+}
+
+func startRun(ctx context.Context, db *sql.DB, kind RunKind, source, key string, restart bool) (*Run, error) {
+	q := database.New(db)
+	run := &Run{db: db, q: q, done: map[string]bool{}}
+
+	open, err := q.GetOpenScrapeRun(ctx, database.GetOpenScrapeRunParams{Kind: string(kind), Source: source})
+	switch {
+	case err == nil && !restart && open.RunKey == key:
+		rows, err := q.ListScrapeRunUrls(ctx, open.ID)
+		if err != nil {
+			return nil, err
+		}
+		run.id, run.page, run.resumed = open.ID, int(open.Page), true
+		for _, row := range rows {
+			run.urls = append(run.urls, row.Url)
+			run.done[row.Url] = row.DoneAt.Valid
+			if !row.DoneAt.Valid {
+				run.left++
+			}
+		}
+		return run, nil
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	// a different url list or start url makes the old progress useless
+	if err := q.DeleteOpenScrapeRuns(ctx, database.DeleteOpenScrapeRunsParams{Kind: string(kind), Source: source}); err != nil {
+		return nil, err
+	}
+	created, err := q.CreateScrapeRun(ctx, database.CreateScrapeRunParams{Kind: string(kind), Source: source, RunKey: key})
+	if err != nil {
+		return nil, err
+	}
+	run.id = created.ID
+	return run, nil
+}
+
+// stores urls not known to the run yet, in one transaction
+func (r *Run) add(ctx context.Context, urls []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	querier := r.q.WithTx(tx)
+
+	var added []string
+	for _, url := range urls {
+		if _, ok := r.done[url]; ok {
+			continue
+		}
+		params := database.InsertScrapeRunUrlParams{RunID: r.id, Position: int64(len(r.urls) + len(added)), Url: url}
+		if err := querier.InsertScrapeRunUrl(ctx, params); err != nil {
+			return err
+		}
+		r.done[url] = false
+		added = append(added, url)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.urls = append(r.urls, added...)
+	r.left += len(added)
+	return nil
+}
+
+// Resumed tells whether the run continues an interrupted one
+func (r *Run) Resumed() bool { return r.resumed }
+
+// Page is the last listing page a collect run saved
+func (r *Run) Page() int { return r.page }
+
+// URLs returns every url of the run, collected so far for collect runs
+func (r *Run) URLs() []string { return r.urls }
+
+// Pending returns the urls a scrape run has not finished yet, in the original order
+func (r *Run) Pending() []string {
+	pending := make([]string, 0, r.left)
+	for _, url := range r.urls {
+		if !r.done[url] {
+			pending = append(pending, url)
+		}
+	}
+	return pending
+}
+
+// Left is the number of pending urls
+func (r *Run) Left() int { return r.left }
+
+// Done marks a url of a scrape run finished, the run finishes with its last url
+func (r *Run) Done(ctx context.Context, url string) error {
+	if done, ok := r.done[url]; !ok || done {
+		return nil
+	}
+	if err := r.q.MarkScrapeRunUrlDone(ctx, database.MarkScrapeRunUrlDoneParams{RunID: r.id, Url: url}); err != nil {
+		return err
+	}
+	r.done[url] = true
+	r.left--
+	if r.left == 0 {
+		return r.Finish(ctx)
+	}
+	return nil
+}
+
+// Save records a listing page of a collect run and the urls found on it
+func (r *Run) Save(ctx context.Context, page int, found []string) error {
+	if err := r.add(ctx, found); err != nil {
+		return err
+	}
+	if err := r.q.UpdateScrapeRunPage(ctx, database.UpdateScrapeRunPageParams{Page: int64(page), ID: r.id}); err != nil {
+		return err
+	}
+	r.page = page
+	return nil
+}
+
+// Finish closes the run and drops its url list, the next start begins from scratch
+func (r *Run) Finish(ctx context.Context) error {
+	if err := r.q.FinishScrapeRun(ctx, r.id); err != nil {
+		return err
+	}
+	return r.q.DeleteScrapeRunUrls(ctx, r.id)
+}
@@ -0,0 +1,122 @@
+package iternal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapeRunResumes(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	urls := []string{"https://justjoin.it/job-offer/a", "https://justjoin.it/job-offer/b", "https://justjoin.it/job-offer/c"}
+
+	run, err := StartScrapeRun(ctx, db, "justjoin.it", urls, urls, false)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed())
+	assert.Equal(t, urls, run.Pending())
+
+	// b was saved before the run got interrupted
+	runs := Runs{"justjoin.it": run}
+	runs.done(ctx, scraper.JobOffer{URL: urls[1], Source: "justjoin.it"})
+	runs.done(ctx, scraper.JobOffer{URL: "https://nofluffjobs.com/pl/job/x", Source: "nofluffjobs.com"})
+
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls, urls, false)
+	require.NoError(t, err)
+	assert.True(t, run.Resumed())
+	assert.Equal(t, []string{urls[0], urls[2]}, run.Pending())
+
+	// the freshness filter drops b once it got scraped, the listed urls still key the run
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls, []string{urls[0], urls[2]}, false)
+	require.NoError(t, err)
+	assert.True(t, run.Resumed())
+	assert.Equal(t, []string{urls[0], urls[2]}, run.Pending())
+
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls, urls, true)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed(), "restart ignores progress")
+	assert.Len(t, run.Pending(), 3)
+
+	// a freshly collected list starts over
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls[:2], urls[:2], false)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed())
+
+	for _, url := range urls[:2] {
+		require.NoError(t, run.Done(ctx, url))
+	}
+	assert.Equal(t, 0, run.Left())
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls[:2], urls[:2], false)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed(), "finished run is not resumed")
+	assert.Len(t, run.Pending(), 2)
+}
+
+// emits the given offers as a scraper would
+type offersScraper []scraper.JobOffer
+
+func (s offersScraper) Source() string { return "justjoin.it" }
+
+func (s offersScraper) Scrape(ctx context.Context, q chan<- scraper.JobOffer) error {
+	for _, job := range s {
+		q <- job
+	}
+	return nil
+}
+
+func TestFailedURLFinishesScrapeRun(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	urls := []string{"https://justjoin.it/job-offer/a", "https://justjoin.it/job-offer/b"}
+
+	run, err := StartScrapeRun(ctx, db, "justjoin.it", urls, urls, false)
+	require.NoError(t, err)
+	offers := offersScraper{
+		{Title: "Go Developer", URL: urls[0], Source: "justjoin.it"},
+		{URL: urls[1], Source: "justjoin.it", Err: errors.New("browser crashed")},
+	}
+	require.NoError(t, StartCollector(ctx, db, []scraper.Scraper{offers}, false, skills.New(nil), nil, Runs{"justjoin.it": run}, nil))
+	assert.Equal(t, 0, run.Left(), "the failed url went to retry-failed")
+
+	run, err = StartScrapeRun(ctx, db, "justjoin.it", urls, urls, false)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed(), "a url that keeps failing doesn't keep the run open")
+	failed, err := ListFailedURLs(ctx, db, "justjoin.it")
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, urls[1], failed[0].URL)
+}
+
+func TestCollectRunResumes(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	start := "https://it.pracuj.pl/praca"
+
+	run, err := StartCollectRun(ctx, db, "pracuj.pl", start, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.Page())
+	require.NoError(t, run.Save(ctx, 1, []string{"https://www.pracuj.pl/praca/a", "https://www.pracuj.pl/praca/b"}))
+	require.NoError(t, run.Save(ctx, 2, []string{"https://www.pracuj.pl/praca/b", "https://www.pracuj.pl/praca/c"}))
+
+	run, err = StartCollectRun(ctx, db, "pracuj.pl", start, false)
+	require.NoError(t, err)
+	assert.True(t, run.Resumed())
+	assert.Equal(t, 2, run.Page())
+	assert.Equal(t, []string{"https://www.pracuj.pl/praca/a", "https://www.pracuj.pl/praca/b", "https://www.pracuj.pl/praca/c"}, run.URLs())
+
+	// another start url is another listing
+	other, err := StartCollectRun(ctx, db, "pracuj.pl", start+"?its=agile", false)
+	require.NoError(t, err)
+	assert.False(t, other.Resumed())
+	assert.Empty(t, other.URLs())
+
+	require.NoError(t, other.Finish(ctx))
+	run, err = StartCollectRun(ctx, db, "pracuj.pl", start+"?its=agile", false)
+	require.NoError(t, err)
+	assert.False(t, run.Resumed())
+}
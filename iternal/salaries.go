@@ -0,0 +1,57 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/salary"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// replaces the structured salary rows of an offer with ones parsed from the raw salary texts,
+// texts without any amount are only kept raw in job_offers
+func saveSalaries(ctx context.Context, q *database.Queries, offerID string, job scraper.JobOffer) error {
+	if err := q.DeleteJobOfferSalaries(ctx, offerID); err != nil {
+		return err
+	}
+
+	raws := []struct {
+		text     string
+		contract salary.Contract
+	}{
+		{job.SalaryEmployment, salary.ContractEmployment},
+		{job.SalaryB2B, salary.ContractB2B},
+		{job.SalaryContract, salary.ContractMandate},
+	}
+
+	for _, raw := range raws {
+		if raw.text == "" {
+			continue
+		}
+		s, ok := salary.Parse(raw.text, raw.contract)
+		if !ok {
+			log.Printf("Could not parse salary %q for %s", raw.text, job.URL)
+			continue
+		}
+		params := database.UpsertJobOfferSalaryParams{
+			JobOfferID:   offerID,
+			ContractType: string(s.Contract),
+			MinAmount:    s.Min,
+			MaxAmount:    s.Max,
+			Currency:     sql.NullString{String: s.Currency, Valid: s.Currency != ""},
+			Period:       string(s.Period),
+			MonthlyMin:   s.MonthlyMin(),
+			MonthlyMax:   s.MonthlyMax(),
+			Raw:          s.Raw,
+		}
+		if s.Gross != nil {
+			params.Gross = sql.NullBool{Bool: *s.Gross, Valid: true}
+		}
+		if err := q.UpsertJobOfferSalary(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
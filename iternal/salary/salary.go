@@ -0,0 +1,195 @@
+package salary
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Period string
+
+const (
+	PeriodHour  Period = "hour"
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+type Contract string
+
+const (
+	ContractEmployment Contract = "employment"
+	ContractB2B        Contract = "b2b"
+	ContractMandate    Contract = "mandate"
+)
+
+// used to turn hourly/daily/yearly rates into a comparable monthly amount
+const (
+	hoursPerMonth = 168
+	daysPerMonth  = 21
+)
+
+// Salary is a parsed salary line, Raw keeps the scraped text for auditing.
+// Gross is nil when the text says neither gross nor net.
+type Salary struct {
+	Min      float64
+	Max      float64
+	Currency string
+	Period   Period
+	Gross    *bool
+	Contract Contract
+	Raw      string
+}
+
+var (
+	// 18 000, 18.000, 150, 150,50, 20k
+	amountRe = regexp.MustCompile(`\d{1,3}(?:[ .]\d{3})+(?:,\d{1,2})?|\d+(?:[.,]\d{1,2})?(?:[kK]\b)?`)
+	netRe    = regexp.MustCompile(`\b(netto|net|nett)\b|\+\s*vat`)
+	grossRe  = regexp.MustCompile(`\b(brutto|gross)\b`)
+)
+
+var currencies = []struct {
+	code    string
+	markers []string
+}{
+	{"PLN", []string{"pln", "zł"}},
+	{"EUR", []string{"eur", "€"}},
+	{"USD", []string{"usd", "$"}},
+	{"GBP", []string{"gbp", "£"}},
+	{"CHF", []string{"chf"}},
+}
+
+var periods = []struct {
+	period  Period
+	markers []string
+}{
+	{PeriodHour, []string{"godz", "hour", "/h", "/ h", "hourly", "per h"}},
+	{PeriodDay, []string{"dzień", "dzien", "dzienn", "day", "daily"}},
+	{PeriodYear, []string{"rok", "roczn", "year", "annual"}},
+	{PeriodMonth, []string{"mies", "month", "mth"}},
+}
+
+var contracts = []struct {
+	contract Contract
+	markers  []string
+}{
+	{ContractB2B, []string{"b2b", "kontrakt"}},
+	{ContractMandate, []string{"zlecen", "mandate", "(uz)", "specific-task", "o dzieło"}},
+	{ContractEmployment, []string{"o pracę", "o prace", "(uop)", "employment", "permanent"}},
+}
+
+func normalizeSpaces(s string) string {
+	s = strings.NewReplacer(" ", " ", " ", " ", " ", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func parseAmount(token string) (float64, bool) {
+	token = strings.TrimSpace(token)
+	multiplier := 1.0
+	if strings.HasSuffix(token, "k") || strings.HasSuffix(token, "K") {
+		multiplier = 1000
+		token = strings.TrimSpace(token[:len(token)-1])
+	}
+	// thousands separators are spaces or dots, decimals use a comma
+	if strings.Contains(token, " ") || strings.Count(token, ".") > 1 ||
+		(strings.Contains(token, ".") && len(token)-strings.LastIndex(token, ".") == 4) {
+		token = strings.NewReplacer(" ", "", ".", "").Replace(token)
+	}
+	token = strings.ReplaceAll(token, ",", ".")
+	v, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v * multiplier, true
+}
+
+// Parse reads amounts, currency, period and gross/net out of a scraped salary line.
+// contract is the field the text came from, empty means detect it from the text.
+// Period defaults to month because every board shows monthly pay unless stated otherwise.
+// Returns false when no amount is found.
+func Parse(raw string, contract Contract) (Salary, bool) {
+	s := Salary{Raw: raw, Contract: contract}
+	text := normalizeSpaces(raw)
+	lower := strings.ToLower(text)
+
+	var amounts []float64
+	for _, token := range amountRe.FindAllString(text, -1) {
+		if v, ok := parseAmount(token); ok && v > 0 {
+			amounts = append(amounts, v)
+		}
+		if len(amounts) == 2 {
+			break
+		}
+	}
+	if len(amounts) == 0 {
+		return s, false
+	}
+	s.Min, s.Max = amounts[0], amounts[0]
+	if len(amounts) == 2 {
+		s.Max = amounts[1]
+		if s.Max < s.Min {
+			s.Min, s.Max = s.Max, s.Min
+		}
+	}
+
+	for _, c := range currencies {
+		if containsAny(lower, c.markers) {
+			s.Currency = c.code
+			break
+		}
+	}
+
+	s.Period = PeriodMonth
+	for _, p := range periods {
+		if containsAny(lower, p.markers) {
+			s.Period = p.period
+			break
+		}
+	}
+
+	switch {
+	case grossRe.MatchString(lower):
+		gross := true
+		s.Gross = &gross
+	case netRe.MatchString(lower):
+		gross := false
+		s.Gross = &gross
+	}
+
+	if s.Contract == "" {
+		for _, c := range contracts {
+			if containsAny(lower, c.markers) {
+				s.Contract = c.contract
+				break
+			}
+		}
+	}
+
+	return s, true
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func toMonthly(amount float64, period Period) float64 {
+	switch period {
+	case PeriodHour:
+		return amount * hoursPerMonth
+	case PeriodDay:
+		return amount * daysPerMonth
+	case PeriodYear:
+		return amount / 12
+	default:
+		return amount
+	}
+}
+
+// MonthlyMin and MonthlyMax make hourly, daily and yearly rates sortable against monthly ones
+func (s Salary) MonthlyMin() float64 { return toMonthly(s.Min, s.Period) }
+func (s Salary) MonthlyMax() float64 { return toMonthly(s.Max, s.Period) }
@@ -0,0 +1,88 @@
+package salary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		contract Contract
+		expected Salary
+	}{
+		{
+			name:     "pracuj employment",
+			raw:      "18 000–24 000 zł \n      brutto / mies.\n      (umowa o pracę)",
+			contract: ContractEmployment,
+			expected: Salary{Min: 18000, Max: 24000, Currency: "PLN", Period: PeriodMonth, Gross: boolPtr(true), Contract: ContractEmployment},
+		},
+		{
+			name:     "pracuj b2b hourly",
+			raw:      "150–190 zł \n      netto (+ VAT) / godz.\n      (kontrakt B2B)",
+			contract: ContractB2B,
+			expected: Salary{Min: 150, Max: 190, Currency: "PLN", Period: PeriodHour, Gross: boolPtr(false), Contract: ContractB2B},
+		},
+		{
+			name:     "nofluff b2b",
+			raw:      "20 000 – 28 000 PLN + VAT (B2B) miesięcznie ",
+			contract: ContractB2B,
+			expected: Salary{Min: 20000, Max: 28000, Currency: "PLN", Period: PeriodMonth, Gross: boolPtr(false), Contract: ContractB2B},
+		},
+		{
+			name:     "nofluff mandate detected from text",
+			raw:      "12 000 – 16 000 PLN brutto (UZ) miesięcznie",
+			expected: Salary{Min: 12000, Max: 16000, Currency: "PLN", Period: PeriodMonth, Gross: boolPtr(true), Contract: ContractMandate},
+		},
+		{
+			name:     "justjoin permanent",
+			raw:      "18 000 - 22 000 PLN, gross per month - permanent",
+			contract: ContractEmployment,
+			expected: Salary{Min: 18000, Max: 22000, Currency: "PLN", Period: PeriodMonth, Gross: boolPtr(true), Contract: ContractEmployment},
+		},
+		{
+			name:     "single amount with nbsp",
+			raw:      "9 500 zł brutto / mies.",
+			expected: Salary{Min: 9500, Max: 9500, Currency: "PLN", Period: PeriodMonth, Gross: boolPtr(true)},
+		},
+		{
+			name:     "k suffix yearly eur",
+			raw:      "60k-80k EUR per year",
+			expected: Salary{Min: 60000, Max: 80000, Currency: "EUR", Period: PeriodYear},
+		},
+		{
+			name:     "decimal daily",
+			raw:      "1 200,50 - 1 500 PLN net/day (B2B)",
+			expected: Salary{Min: 1200.5, Max: 1500, Currency: "PLN", Period: PeriodDay, Gross: boolPtr(false), Contract: ContractB2B},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := Parse(tc.raw, tc.contract)
+			require.True(t, ok)
+			tc.expected.Raw = tc.raw
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseWithoutAmount(t *testing.T) {
+	_, ok := Parse("wynagrodzenie do negocjacji", ContractEmployment)
+	assert.False(t, ok)
+}
+
+func TestMonthly(t *testing.T) {
+	s := Salary{Min: 150, Max: 190, Period: PeriodHour}
+	assert.Equal(t, 150.0*168, s.MonthlyMin())
+	assert.Equal(t, 190.0*168, s.MonthlyMax())
+
+	s = Salary{Min: 120000, Max: 180000, Period: PeriodYear}
+	assert.Equal(t, 10000.0, s.MonthlyMin())
+	assert.Equal(t, 15000.0, s.MonthlyMax())
+}
@@ -0,0 +1,201 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+)
+
+// CaptchaAction tells a scraper what to do with a url that answered with a captcha
+type CaptchaAction int
+
+const (
+	// fetch the url again right away
+	CaptchaRetry CaptchaAction = iota
+	// move the url to the end of the list
+	CaptchaRequeue
+	// leave the url for the next run
+	CaptchaSkip
+)
+
+// ErrCaptchaAbort stops the scraper of the source that hit a captcha
+var ErrCaptchaAbort = errors.New("captcha, source aborted")
+
+// CaptchaStrategy decides what happens to a url that hit a captcha
+type CaptchaStrategy interface {
+	Handle(ctx context.Context, source, url string) (CaptchaAction, error)
+}
+
+// PauseCaptcha asks for the captcha to be solved in the browser and waits for enter
+// or the timeout, whichever comes first, then retries. Prompts of parallel scrapers
+// take turns and share one reader, so an unattended run only loses the timeout.
+type PauseCaptcha struct {
+	mu      sync.Mutex
+	lines   <-chan struct{}
+	timeout time.Duration
+}
+
+func NewPauseCaptcha(in io.Reader, timeout time.Duration) *PauseCaptcha {
+	lines := make(chan struct{})
+	go func() {
+		// closed on EOF, a closed stdin just waits out the timeout
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- struct{}{}
+		}
+	}()
+	return &PauseCaptcha{lines: lines, timeout: timeout}
+}
+
+func (p *PauseCaptcha) Handle(ctx context.Context, source, url string) (CaptchaAction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	log.Printf("%s: captcha at %s, solve it in the browser and press enter (retrying in %s anyway)", source, url, p.timeout)
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	lines := p.lines
+	for {
+		select {
+		case <-ctx.Done():
+			return CaptchaSkip, ctx.Err()
+		case <-timer.C:
+			return CaptchaRetry, nil
+		case _, ok := <-lines:
+			if ok {
+				return CaptchaRetry, nil
+			}
+			lines = nil
+		}
+	}
+}
+
+// RequeueCaptcha moves the url to the end of the list, by then the fetcher has backed off
+type RequeueCaptcha struct{}
+
+func (RequeueCaptcha) Handle(context.Context, string, string) (CaptchaAction, error) {
+	return CaptchaRequeue, nil
+}
+
+// AbortCaptcha stops the source on its first captcha, the other sources keep going
+type AbortCaptcha struct{}
+
+func (AbortCaptcha) Handle(_ context.Context, _, url string) (CaptchaAction, error) {
+	return CaptchaSkip, fmt.Errorf("%w at %s", ErrCaptchaAbort, url)
+}
+
+// CaptchaCounts is what happened to the captchas of one source
+type CaptchaCounts struct {
+	Source   string
+	Hits     int
+	Retried  int
+	Requeued int
+	Skipped  int
+	Aborted  bool
+}
+
+// Captchas puts a strategy behind a per url attempt limit, so no url loops forever,
+// and counts every captcha per source
+type Captchas struct {
+	strategy    CaptchaStrategy
+	maxAttempts int
+
+	mu       sync.Mutex
+	attempts map[string]int
+	counts   map[string]*CaptchaCounts
+}
+
+func NewCaptchas(strategy CaptchaStrategy, maxAttempts int) *Captchas {
+	return &Captchas{
+		strategy:    strategy,
+		maxAttempts: max(maxAttempts, 1),
+		attempts:    map[string]int{},
+		counts:      map[string]*CaptchaCounts{},
+	}
+}
+
+// CaptchasFromConfig builds the configured strategy, pause prompts read from in
+func CaptchasFromConfig(cfg config.Captcha, in io.Reader) *Captchas {
+	var strategy CaptchaStrategy
+	switch cfg.Strategy {
+	case config.CaptchaRequeue:
+		strategy = RequeueCaptcha{}
+	case config.CaptchaAbort:
+		strategy = AbortCaptcha{}
+	default:
+		strategy = NewPauseCaptcha(in, cfg.Timeout)
+	}
+	return NewCaptchas(strategy, cfg.MaxAttempts)
+}
+
+func (c *Captchas) Handle(ctx context.Context, source, url string) (CaptchaAction, error) {
+	c.mu.Lock()
+	c.attempts[source+" "+url]++
+	exhausted := c.attempts[source+" "+url] > c.maxAttempts
+	c.source(source).Hits++
+	c.mu.Unlock()
+
+	action, err := CaptchaSkip, error(nil)
+	if exhausted {
+		log.Printf("%s: captcha at %s %d times, skipping it until the next run", source, url, c.maxAttempts)
+	} else {
+		action, err = c.strategy.Handle(ctx, source, url)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.source(source)
+	switch {
+	case errors.Is(err, ErrCaptchaAbort):
+		counts.Aborted = true
+	case err != nil:
+	case action == CaptchaRetry:
+		counts.Retried++
+	case action == CaptchaRequeue:
+		counts.Requeued++
+	default:
+		counts.Skipped++
+	}
+	return action, err
+}
+
+func (c *Captchas) source(source string) *CaptchaCounts {
+	counts, ok := c.counts[source]
+	if !ok {
+		counts = &CaptchaCounts{Source: source}
+		c.counts[source] = counts
+	}
+	return counts
+}
+
+// Counts returns the captcha counts of every source that hit one, by source name
+func (c *Captchas) Counts() []CaptchaCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make([]CaptchaCounts, 0, len(c.counts))
+	for _, s := range c.counts {
+		counts = append(counts, *s)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Source < counts[j].Source })
+	return counts
+}
+
+// LogCounts prints the captcha summary of the run
+func (c *Captchas) LogCounts() {
+	for _, s := range c.Counts() {
+		line := fmt.Sprintf("%s: %d captchas, %d retried, %d requeued, %d skipped", s.Source, s.Hits, s.Retried, s.Requeued, s.Skipped)
+		if s.Aborted {
+			line += ", source aborted"
+		}
+		log.Print(line)
+	}
+}
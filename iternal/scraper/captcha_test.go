@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseCaptchaTimesOut(t *testing.T) {
+	// nobody at the keyboard, stdin already at EOF
+	c := NewCaptchas(NewPauseCaptcha(strings.NewReader(""), 20*time.Millisecond), 2)
+
+	start := time.Now()
+	action, err := c.Handle(context.Background(), "pracuj.pl", "https://pracuj.pl/1")
+	require.NoError(t, err)
+	assert.Equal(t, CaptchaRetry, action)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	_, err = c.Handle(context.Background(), "pracuj.pl", "https://pracuj.pl/1")
+	require.NoError(t, err)
+	action, err = c.Handle(context.Background(), "pracuj.pl", "https://pracuj.pl/1")
+	require.NoError(t, err)
+	assert.Equal(t, CaptchaSkip, action, "max_attempts reached")
+
+	assert.Equal(t, []CaptchaCounts{{Source: "pracuj.pl", Hits: 3, Retried: 2, Skipped: 1}}, c.Counts())
+}
+
+func TestPauseCaptchaEnterAndCancel(t *testing.T) {
+	in, out := io.Pipe()
+	defer out.Close()
+	pause := NewPauseCaptcha(in, time.Hour)
+
+	go out.Write([]byte("\n"))
+	action, err := pause.Handle(context.Background(), "nofluffjobs.com", "https://nofluffjobs.com/1")
+	require.NoError(t, err)
+	assert.Equal(t, CaptchaRetry, action)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pause.Handle(ctx, "nofluffjobs.com", "https://nofluffjobs.com/1")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCaptchasFromConfig(t *testing.T) {
+	c := CaptchasFromConfig(config.Captcha{Strategy: config.CaptchaRequeue, MaxAttempts: 1}, nil)
+	action, err := c.Handle(context.Background(), "justjoin.it", "https://justjoin.it/1")
+	require.NoError(t, err)
+	assert.Equal(t, CaptchaRequeue, action)
+
+	c = CaptchasFromConfig(config.Captcha{Strategy: config.CaptchaAbort, MaxAttempts: 1}, nil)
+	_, err = c.Handle(context.Background(), "justjoin.it", "https://justjoin.it/1")
+	assert.ErrorIs(t, err, ErrCaptchaAbort)
+	assert.True(t, c.Counts()[0].Aborted)
+}
@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePublished reads the publication date a board shows, absolute ("12 października 2025",
+// "2025-10-12") or relative to now ("3 dni temu", "yesterday"), a date without a year is the
+// last one before now
+func ParsePublished(text string, now time.Time) (time.Time, bool) {
+	return parseDate(text, now, false)
+}
+
+// ParseExpires reads the date an offer stops being valid, "ważna jeszcze 5 dni" and
+// "expires in 5 days" count from now, a date without a year is the next one after now
+func ParseExpires(text string, now time.Time) (time.Time, bool) {
+	return parseDate(text, now, true)
+}
+
+var (
+	isoDateRe     = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:t\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:z|[+-]\d{2}:?\d{2})?)?`)
+	numericDateRe = regexp.MustCompile(`(?:^|[^\d])(\d{1,2})[./](\d{1,2})[./](\d{4})`)
+	// "12 października 2025", "10 gru", "12 october"
+	dayMonthRe = regexp.MustCompile(`(?:^|[^\p{L}\d])(\d{1,2})\s+(\p{L}+)\.?(?:\s+(\d{4}))?`)
+	// "october 12, 2025", "oct 12th"
+	monthDayRe = regexp.MustCompile(`(?:^|[^\p{L}])(\p{L}+)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?`)
+	relativeRe = regexp.MustCompile(`(?:^|[^\p{L}\d])(\d+|an?|one|jeden|jedna|jedną)?\s*(minut[ay]?|minutę|mins?|minutes?|godzin[ay]?|godzinę|godz|hours?|hrs?|dni|dnia|dzień|days?|tygodni[e]?|tydzień|weeks?|miesiąc[ae]?|miesięcy|months?)(?:$|[^\p{L}])`)
+)
+
+// first three letters of month names, polish in any grammatical case and english
+var monthPrefixes = map[string]time.Month{
+	"sty": time.January, "jan": time.January,
+	"lut": time.February, "feb": time.February,
+	"mar": time.March,
+	"kwi": time.April, "apr": time.April,
+	"maj": time.May, "may": time.May,
+	"cze": time.June, "jun": time.June,
+	"lip": time.July, "jul": time.July,
+	"sie": time.August, "aug": time.August,
+	"wrz": time.September, "sep": time.September,
+	"paź": time.October, "paz": time.October, "oct": time.October,
+	"lis": time.November, "nov": time.November,
+	"gru": time.December, "dec": time.December,
+}
+
+var (
+	pastMarkers   = []string{"temu", "ago"}
+	futureMarkers = []string{"jeszcze", "za ", "in ", "left", "pozostał", "wygasa", "expires"}
+)
+
+func parseDate(text string, now time.Time, future bool) (time.Time, bool) {
+	text = strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(text, " ", " ")), " "))
+	if text == "" {
+		return time.Time{}, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if m := isoDateRe.FindString(text); m != "" {
+		m = strings.ToUpper(m)
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+			if t, err := time.Parse(layout, m); err == nil {
+				return t, true
+			}
+		}
+		if t, err := time.ParseInLocation("2006-01-02", m[:10], now.Location()); err == nil {
+			return t, true
+		}
+	}
+
+	if m := numericDateRe.FindStringSubmatch(text); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		if t, ok := calendarDate(year, time.Month(month), day, now.Location()); ok {
+			return t, true
+		}
+	}
+
+	for _, m := range dayMonthRe.FindAllStringSubmatch(text, -1) {
+		if t, ok := namedDate(m[1], m[2], m[3], today, future); ok {
+			return t, true
+		}
+	}
+	for _, m := range monthDayRe.FindAllStringSubmatch(text, -1) {
+		if t, ok := namedDate(m[2], m[1], m[3], today, future); ok {
+			return t, true
+		}
+	}
+
+	sign := 1
+	if !future {
+		sign = -1
+	}
+	if containsAny(text, pastMarkers) {
+		sign = -1
+	} else if containsAny(text, futureMarkers) {
+		sign = 1
+	}
+
+	switch {
+	// before "wczoraj", which it contains
+	case strings.Contains(text, "przedwczoraj"):
+		return today.AddDate(0, 0, -2), true
+	case strings.Contains(text, "wczoraj"), strings.Contains(text, "yesterday"):
+		return today.AddDate(0, 0, -1), true
+	case strings.Contains(text, "jutro"), strings.Contains(text, "tomorrow"):
+		return today.AddDate(0, 0, 1), true
+	case strings.Contains(text, "dzisiaj"), strings.Contains(text, "dziś"), strings.Contains(text, "today"),
+		strings.Contains(text, "przed chwilą"), strings.Contains(text, "just now"):
+		return today, true
+	}
+
+	if m := relativeRe.FindStringSubmatch(text); m != nil {
+		n := 1
+		if v, err := strconv.Atoi(m[1]); err == nil {
+			n = v
+		}
+		n *= sign
+		unit := m[2]
+		switch {
+		case strings.HasPrefix(unit, "min"):
+			return now.Add(time.Duration(n) * time.Minute).Truncate(time.Minute), true
+		case strings.HasPrefix(unit, "godz"), strings.HasPrefix(unit, "h"):
+			return now.Add(time.Duration(n) * time.Hour).Truncate(time.Minute), true
+		case strings.HasPrefix(unit, "t"), strings.HasPrefix(unit, "w"):
+			return today.AddDate(0, 0, 7*n), true
+		case strings.HasPrefix(unit, "mies"), strings.HasPrefix(unit, "mon"):
+			return today.AddDate(0, n, 0), true
+		default:
+			return today.AddDate(0, 0, n), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// day and month name with an optional year, without one the year closest to today in the wanted direction
+func namedDate(dayText, monthText, yearText string, today time.Time, future bool) (time.Time, bool) {
+	month, ok := monthPrefixes[string([]rune(monthText)[:min(3, len([]rune(monthText)))])]
+	if !ok {
+		return time.Time{}, false
+	}
+	day, _ := strconv.Atoi(dayText)
+	if yearText != "" {
+		year, _ := strconv.Atoi(yearText)
+		return calendarDate(year, month, day, today.Location())
+	}
+	t, ok := calendarDate(today.Year(), month, day, today.Location())
+	if !ok {
+		return time.Time{}, false
+	}
+	switch {
+	case future && t.Before(today):
+		t = t.AddDate(1, 0, 0)
+	case !future && t.After(today):
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, true
+}
+
+// rejects days time.Date would roll over into the next month
+func calendarDate(year int, month time.Month, day int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	if t.Day() != day || t.Month() != month {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,63 @@
+package scraper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDates(t *testing.T) {
+	now := time.Date(2025, time.November, 17, 14, 30, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expires  bool
+		expected time.Time
+	}{
+		{name: "json-ld timestamp", text: "2025-10-20T08:15:00.000Z", expected: time.Date(2025, time.October, 20, 8, 15, 0, 0, time.UTC)},
+		{name: "json-ld date", text: "2025-12-31", expires: true, expected: day(2025, time.December, 31)},
+		{name: "numeric polish", text: "Oferta ważna do 30.11.2025", expires: true, expected: day(2025, time.November, 30)},
+		{name: "polish month with year", text: "Opublikowana: 12 października 2025", expected: day(2025, time.October, 12)},
+		{name: "polish month ahead", text: "do: 10 grudnia", expires: true, expected: day(2025, time.December, 10)},
+		{name: "polish month published last year", text: "10 grudnia", expected: day(2024, time.December, 10)},
+		{name: "expiry early next year", text: "do: 5 sty", expires: true, expected: day(2026, time.January, 5)},
+		{name: "english month first", text: "Posted on October 3rd, 2025", expected: day(2025, time.October, 3)},
+		{name: "dzisiaj", text: "Opublikowano dzisiaj", expected: day(2025, time.November, 17)},
+		{name: "wczoraj", text: "wczoraj", expected: day(2025, time.November, 16)},
+		{name: "przedwczoraj", text: "przedwczoraj", expected: day(2025, time.November, 15)},
+		{name: "yesterday", text: "Published yesterday", expected: day(2025, time.November, 16)},
+		{name: "polish days ago", text: "3 dni temu", expected: day(2025, time.November, 14)},
+		{name: "english days ago", text: "Published: 2 days ago", expected: day(2025, time.November, 15)},
+		{name: "an hour ago", text: "an hour ago", expected: time.Date(2025, time.November, 17, 13, 30, 0, 0, time.UTC)},
+		{name: "polish hours ago", text: "5 godzin temu", expected: time.Date(2025, time.November, 17, 9, 30, 0, 0, time.UTC)},
+		{name: "week without number", text: "tydzień temu", expected: day(2025, time.November, 10)},
+		{name: "months ago", text: "2 months ago", expected: day(2025, time.September, 17)},
+		{name: "pracuj validity", text: "ważna jeszcze 23 dni", expires: true, expected: day(2025, time.December, 10)},
+		{name: "pracuj last day", text: "ważna jeszcze 1 dzień", expires: true, expected: day(2025, time.November, 18)},
+		{name: "expires in", text: "Expires in 14 days", expires: true, expected: day(2025, time.December, 1)},
+		{name: "days left", text: "5 days left", expires: true, expected: day(2025, time.November, 22)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parse := scraper.ParsePublished
+			if tc.expires {
+				parse = scraper.ParseExpires
+			}
+			got, ok := parse(tc.text, now)
+			assert.True(t, ok)
+			assert.True(t, tc.expected.Equal(got), "got %s, want %s", got, tc.expected)
+		})
+	}
+
+	for _, text := range []string{"", "Praca od zaraz", "pełny etat", "31.02.2025"} {
+		_, ok := scraper.ParsePublished(text, now)
+		assert.False(t, ok, "%q is not a date", text)
+	}
+}
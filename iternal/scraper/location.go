@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type WorkMode string
+
+const (
+	WorkModeRemote WorkMode = "remote"
+	WorkModeHybrid WorkMode = "hybrid"
+	WorkModeOnsite WorkMode = "onsite"
+)
+
+// Location is the normalized form of the raw location text, Country is an ISO code
+type Location struct {
+	Cities   []string `json:"cities,omitempty"`
+	Country  string   `json:"country,omitempty"`
+	WorkMode WorkMode `json:"work_mode,omitempty"`
+}
+
+// lowercase spellings (polish, ascii, english) to canonical city name
+var polishCities = map[string]string{}
+
+// the keys of polishCities longest first, so a prefix match takes the most specific spelling
+var citySpellings []string
+
+func init() {
+	cities := map[string][]string{
+		"Warszawa":            {"warszawa", "warsaw"},
+		"Kraków":              {"kraków", "krakow", "cracow"},
+		"Wrocław":             {"wrocław", "wroclaw"},
+		"Poznań":              {"poznań", "poznan"},
+		"Gdańsk":              {"gdańsk", "gdansk"},
+		"Gdynia":              {"gdynia"},
+		"Sopot":               {"sopot"},
+		"Łódź":                {"łódź", "lodz"},
+		"Katowice":            {"katowice"},
+		"Gliwice":             {"gliwice"},
+		"Chorzów":             {"chorzów", "chorzow"},
+		"Sosnowiec":           {"sosnowiec"},
+		"Bielsko-Biała":       {"bielsko-biała", "bielsko-biala"},
+		"Częstochowa":         {"częstochowa", "czestochowa"},
+		"Lublin":              {"lublin"},
+		"Białystok":           {"białystok", "bialystok"},
+		"Szczecin":            {"szczecin"},
+		"Bydgoszcz":           {"bydgoszcz"},
+		"Toruń":               {"toruń", "torun"},
+		"Rzeszów":             {"rzeszów", "rzeszow"},
+		"Kielce":              {"kielce"},
+		"Olsztyn":             {"olsztyn"},
+		"Opole":               {"opole"},
+		"Zielona Góra":        {"zielona góra", "zielona gora"},
+		"Gorzów Wielkopolski": {"gorzów wielkopolski", "gorzow wielkopolski"},
+		"Radom":               {"radom"},
+		"Płock":               {"płock", "plock"},
+		"Tarnów":              {"tarnów", "tarnow"},
+		"Nowy Sącz":           {"nowy sącz", "nowy sacz"},
+		"Legnica":             {"legnica"},
+		"Koszalin":            {"koszalin"},
+		"Elbląg":              {"elbląg", "elblag"},
+		"Kalisz":              {"kalisz"},
+		"Piła":                {"piła", "pila"},
+		"Zabrze":              {"zabrze"},
+		"Bytom":               {"bytom"},
+		"Tychy":               {"tychy"},
+		"Rybnik":              {"rybnik"},
+		"Lubin":               {"lubin"},
+		"Siedlce":             {"siedlce"},
+	}
+	for canonical, spellings := range cities {
+		for _, s := range spellings {
+			polishCities[s] = canonical
+			citySpellings = append(citySpellings, s)
+		}
+	}
+	slices.SortFunc(citySpellings, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+	})
+}
+
+var (
+	hybridMarkers = []string{"hybryd", "hybrid"}
+	remoteMarkers = []string{"zdaln", "remote", "home office"}
+	onsiteMarkers = []string{"stacjonar", "on-site", "onsite", "office", "biur"}
+	// "rekrutacja zdalna" is about the interview, not the job
+	ignoredMarkers = []string{"rekrutacja zdalna", "remote recruitment"}
+)
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectWorkMode picks the most remote mode mentioned, offers listing both
+// "praca hybrydowa" and "praca zdalna" allow full remote
+func DetectWorkMode(texts ...string) WorkMode {
+	var remote, hybrid, onsite bool
+	for _, text := range texts {
+		lower := strings.ToLower(text)
+		for _, ignored := range ignoredMarkers {
+			lower = strings.ReplaceAll(lower, ignored, "")
+		}
+		remote = remote || containsAny(lower, remoteMarkers)
+		hybrid = hybrid || containsAny(lower, hybridMarkers)
+		onsite = onsite || containsAny(lower, onsiteMarkers)
+	}
+	switch {
+	case remote:
+		return WorkModeRemote
+	case hybrid:
+		return WorkModeHybrid
+	case onsite:
+		return WorkModeOnsite
+	}
+	return ""
+}
+
+// ParseLocation finds known polish cities and the work mode in location texts.
+// Pieces are split on "," and "+", "Gdańsk + 2 Locations" gives just Gdańsk.
+// Offers naming a city but no work mode are treated as onsite.
+func ParseLocation(texts ...string) Location {
+	var loc Location
+	seen := make(map[string]bool)
+
+	for _, text := range texts {
+		pieces := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '+' || r == '\n' || r == '|' })
+		for _, piece := range pieces {
+			key := strings.ToLower(strings.TrimSpace(piece))
+			city, ok := polishCities[key]
+			if !ok {
+				// "Kraków Kapelanka" or "WrocławHybrydowo" style leftovers
+				for _, spelling := range citySpellings {
+					if strings.HasPrefix(key, spelling) && !startsWithLetter(key[len(spelling):]) || strings.HasPrefix(key, spelling+"hybryd") {
+						city, ok = polishCities[spelling], true
+						break
+					}
+				}
+			}
+			if ok && !seen[city] {
+				seen[city] = true
+				loc.Cities = append(loc.Cities, city)
+			}
+		}
+	}
+
+	loc.WorkMode = DetectWorkMode(texts...)
+	if loc.WorkMode == "" && len(loc.Cities) > 0 {
+		loc.WorkMode = WorkModeOnsite
+	}
+
+	lowerAll := strings.ToLower(strings.Join(texts, " "))
+	if len(loc.Cities) > 0 || strings.Contains(lowerAll, "polska") || strings.Contains(lowerAll, "poland") {
+		loc.Country = "PL"
+	}
+	return loc
+}
+
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
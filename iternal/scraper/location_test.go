@@ -0,0 +1,63 @@
+package scraper_test
+
+import (
+	"testing"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		texts    []string
+		expected scraper.Location
+	}{
+		{
+			name:     "pracuj address with remote recruitment",
+			texts:    []string{"Kapelanka 42A, Dębniki, Kraków", "praca hybrydowa", "rekrutacja zdalna"},
+			expected: scraper.Location{Cities: []string{"Kraków"}, Country: "PL", WorkMode: scraper.WorkModeHybrid},
+		},
+		{
+			name:     "nofluff remote",
+			texts:    []string{"Praca zdalna"},
+			expected: scraper.Location{WorkMode: scraper.WorkModeRemote},
+		},
+		{
+			name:     "nofluff glued hybrid pin",
+			texts:    []string{"Hybrydowo", "WrocławHybrydowo"},
+			expected: scraper.Location{Cities: []string{"Wrocław"}, Country: "PL", WorkMode: scraper.WorkModeHybrid},
+		},
+		{
+			name:     "district after a dash or a non-breaking space",
+			texts:    []string{"Wrocław–Krzyki", "Kraków\u00a0Kapelanka"},
+			expected: scraper.Location{Cities: []string{"Wrocław", "Kraków"}, Country: "PL", WorkMode: scraper.WorkModeOnsite},
+		},
+		{
+			name:     "longer name starting with a city",
+			texts:    []string{"Lublinianka", "Łódźka"},
+			expected: scraper.Location{},
+		},
+		{
+			name:     "justjoin multi location english",
+			texts:    []string{"Warsaw + Krakow", "Remote"},
+			expected: scraper.Location{Cities: []string{"Warszawa", "Kraków"}, Country: "PL", WorkMode: scraper.WorkModeRemote},
+		},
+		{
+			name:     "city only means onsite",
+			texts:    []string{"Poznań"},
+			expected: scraper.Location{Cities: []string{"Poznań"}, Country: "PL", WorkMode: scraper.WorkModeOnsite},
+		},
+		{
+			name:     "unknown",
+			texts:    []string{""},
+			expected: scraper.Location{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, scraper.ParseLocation(tc.texts...))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -11,15 +12,24 @@ type JobOffer struct {
 	ID               string   `json:"id"`
 	Title            string   `json:"title"`
 	Company          string   `json:"company"`
+	CompanyURL       string   `json:"company_url,omitempty"` // company page on the board
 	Location         string   `json:"location"`
+	Place            Location `json:"place"` // normalized Location
 	SalaryEmployment string   `json:"salary_employment"`
 	SalaryContract   string   `json:"salary_contract"`
 	SalaryB2B        string   `json:"salary_b2b"`
 	Description      string   `json:"description"`
 	URL              string   `json:"url"`
 	Source           string   `json:"source"`
-	PublishedAt      *string  `json:"published_at,omitempty"` //potencial problems
 	Skills           []string `json:"skills,omitempty"`
+	// dates the offer page shows, nil when it shows none
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// set by scrapers when the offer page is gone or says the offer expired
+	Expired  bool    `json:"expired,omitempty"`
+	ClosedAt *string `json:"closed_at,omitempty"`
+	// set instead of the offer fields when the page could not be scraped
+	Err error `json:"-"`
 }
 
 type Scraper interface {
@@ -27,37 +37,50 @@ type Scraper interface {
 	Scrape(ctx context.Context, q chan<- JobOffer) error
 }
 
-func RunScrapers(ctx context.Context, scrapers []Scraper, parallel bool) chan JobOffer {
+// delay between starting scrapers in parallel mode, so browsers don't launch at once
+var ParallelStartDelay = 5 * time.Second
+
+// RunScrapers streams offers from every scraper into the returned channel,
+// scraper errors go to the second channel, both are closed when all scrapers finish
+func RunScrapers(ctx context.Context, scrapers []Scraper, parallel bool) (chan JobOffer, chan error) {
 	out := make(chan JobOffer)
+	errs := make(chan error, len(scrapers))
 	var wg sync.WaitGroup
 
+	run := func(scr Scraper) {
+		log.Printf("Starting scraper: %s", scr.Source())
+		if err := scr.Scrape(ctx, out); err != nil {
+			log.Printf("Error in scraper %s: %v", scr.Source(), err)
+			errs <- fmt.Errorf("%s: %w", scr.Source(), err)
+		}
+		log.Printf("Finished scraper: %s", scr.Source())
+	}
+
 	go func() {
 		if parallel {
-			for _, s := range scrapers {
-				time.Sleep(5 * time.Second)
+			for i, s := range scrapers {
+				if i > 0 && ParallelStartDelay > 0 {
+					select {
+					case <-ctx.Done():
+					case <-time.After(ParallelStartDelay):
+					}
+				}
 				wg.Add(1)
 				go func(scr Scraper) {
 					defer wg.Done()
-					log.Printf("Starting scraper: %s", scr.Source())
-					if err := scr.Scrape(ctx, out); err != nil {
-						log.Printf("Error in scraper %s: %v", scr.Source(), err)
-					}
-					log.Printf("Finished scraper: %s", scr.Source())
+					run(scr)
 				}(s)
 			}
 			wg.Wait()
 		} else {
 			for _, s := range scrapers {
-				log.Printf("Starting scraper: %s", s.Source())
-				if err := s.Scrape(ctx, out); err != nil {
-					log.Printf("Error in scraper %s: %v", s.Source(), err)
-				}
-				log.Printf("Finished scraper: %s", s.Source())
+				run(s)
 			}
 		}
 
 		close(out)
+		close(errs)
 	}()
 
-	return out
+	return out, errs
 }
@@ -55,7 +55,7 @@ func TestRunScrapersCollectsResults(t *testing.T) {
 		},
 	}
 
-	out := scraper.RunScrapers(context.Background(), []scraper.Scraper{s1, s2})
+	out, _ := scraper.RunScrapers(context.Background(), []scraper.Scraper{s1, s2}, false)
 
 	var results []scraper.JobOffer
 	for o := range out {
@@ -75,13 +75,20 @@ func TestRunScrapersHandlesErrors(t *testing.T) {
 		err:    errors.New("scrape failed"),
 	}
 
-	out := scraper.RunScrapers(context.Background(), []scraper.Scraper{s})
+	out, errs := scraper.RunScrapers(context.Background(), []scraper.Scraper{s}, false)
 	var results []scraper.JobOffer
 	for o := range out {
 		results = append(results, o)
 	}
 
 	assert.Empty(t, results)
+
+	var reported []error
+	for err := range errs {
+		reported = append(reported, err)
+	}
+	assert.Len(t, reported, 1)
+	assert.ErrorContains(t, reported[0], "errScraper")
 }
 
 func TestRunScrapersClosesChannel(t *testing.T) {
@@ -90,7 +97,7 @@ func TestRunScrapersClosesChannel(t *testing.T) {
 		offers: []scraper.JobOffer{},
 	}
 
-	out := scraper.RunScrapers(context.Background(), []scraper.Scraper{s})
+	out, _ := scraper.RunScrapers(context.Background(), []scraper.Scraper{s}, false)
 
 	_, ok := <-out
 	assert.False(t, ok, "kanał powinien być zamknięty")
@@ -109,8 +116,9 @@ func TestRunScrapersParallelExecution(t *testing.T) {
 		offers: []scraper.JobOffer{{ID: "2"}},
 	}
 
+	scraper.ParallelStartDelay = 0
 	start := time.Now()
-	out := scraper.RunScrapers(context.Background(), []scraper.Scraper{s1, s2})
+	out, _ := scraper.RunScrapers(context.Background(), []scraper.Scraper{s1, s2}, true)
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -0,0 +1,23 @@
+package scrapers
+
+import (
+	"net/url"
+	"strings"
+)
+
+// absolute url of href, boards link company pages relative to the offer
+func absoluteURL(pageURL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := base.Parse(href)
+	if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+		return ""
+	}
+	return ref.String()
+}
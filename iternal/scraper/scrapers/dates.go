@@ -0,0 +1,101 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// relative dates ("3 dni temu") count from here, tests pin it
+var now = time.Now
+
+// the schema.org JobPosting boards embed for search engines, the most reliable source of dates
+type jobPosting struct {
+	Type         any          `json:"@type"`
+	DatePosted   string       `json:"datePosted"`
+	ValidThrough string       `json:"validThrough"`
+	Graph        []jobPosting `json:"@graph"`
+}
+
+func (p jobPosting) isJobPosting() bool {
+	switch t := p.Type.(type) {
+	case string:
+		return t == "JobPosting"
+	case []any:
+		for _, v := range t {
+			if v == "JobPosting" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDDates reads datePosted and validThrough of the first JobPosting on the page
+func jsonLDDates(doc *goquery.Document) (published, expires *time.Time) {
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		raw := strings.TrimSpace(s.Text())
+		var postings []jobPosting
+		if strings.HasPrefix(raw, "[") {
+			if json.Unmarshal([]byte(raw), &postings) != nil {
+				return true
+			}
+		} else {
+			var p jobPosting
+			if json.Unmarshal([]byte(raw), &p) != nil {
+				return true
+			}
+			postings = append([]jobPosting{p}, p.Graph...)
+		}
+		for _, p := range postings {
+			if !p.isJobPosting() {
+				continue
+			}
+			published = parsed(scraper.ParsePublished, p.DatePosted)
+			expires = parsed(scraper.ParseExpires, p.ValidThrough)
+			return false
+		}
+		return true
+	})
+	return published, expires
+}
+
+// first of texts that parses as a date
+func parsed(parse func(string, time.Time) (time.Time, bool), texts ...string) *time.Time {
+	for _, text := range texts {
+		if t, ok := parse(text, now()); ok {
+			return &t
+		}
+	}
+	return nil
+}
+
+// dates from the json-ld first, the texts shown on the page fill in what it lacks
+func offerDates(doc *goquery.Document, publishedTexts, expiresTexts []string) (published, expires *time.Time) {
+	published, expires = jsonLDDates(doc)
+	if published == nil {
+		published = parsed(scraper.ParsePublished, publishedTexts...)
+	}
+	if expires == nil {
+		expires = parsed(scraper.ParseExpires, expiresTexts...)
+	}
+	return published, expires
+}
+
+var expiryWords = []string{"ważn", "wygas", "valid", "expire", "do:"}
+
+// sorts the date texts of a page into publication and expiry by their wording
+func dateTexts(texts []string) (published, expires []string) {
+	for _, text := range texts {
+		text = strings.TrimSpace(text)
+		if containsAny(text, expiryWords) {
+			expires = append(expires, text)
+			continue
+		}
+		published = append(published, text)
+	}
+	return published, expires
+}
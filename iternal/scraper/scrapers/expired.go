@@ -0,0 +1,42 @@
+package scrapers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// a site without an expired section never reads a page as taken down
+func (e Expired) matches(doc *goquery.Document) bool {
+	if e.Selector == "" {
+		return false
+	}
+	expired := false
+	doc.Find(e.Selector).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text := strings.ToLower(strings.Join(strings.Fields(s.Text()), " "))
+		for _, phrase := range e.Phrases {
+			if strings.Contains(text, phrase) {
+				expired = true
+				return false
+			}
+		}
+		return true
+	})
+	return expired
+}
+
+// boards answer 404 or 410 for removed offers
+func removedStatus(err error) bool {
+	var statusErr *fetcher.StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone)
+}
+
+// only url and source are known, the collector closes the stored offer
+func expiredOffer(url, source string) scraper.JobOffer {
+	return scraper.JobOffer{URL: url, Source: source, Expired: true}
+}
@@ -0,0 +1,16 @@
+package scrapers
+
+import (
+	"errors"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// reported for urls a captcha strategy gave up on
+var errCaptcha = errors.New("captcha page")
+
+// failedOffer carries a url that could not be scraped to the collector,
+// a removed page (404/410) also closes the offer
+func failedOffer(url, source string, err error) scraper.JobOffer {
+	return scraper.JobOffer{URL: url, Source: source, Expired: removedStatus(err), Err: err}
+}
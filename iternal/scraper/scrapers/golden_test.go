@@ -0,0 +1,86 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./iternal/scraper/scrapers -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite golden json files from the current parsers")
+
+type htmlParser func(html string, url string) (scraper.JobOffer, error, bool)
+
+// testdata/<source>/<name>.html is parsed and compared against testdata/<source>/<name>.golden.json
+var goldenSources = map[string]struct {
+	parser  htmlParser
+	baseURL string
+}{
+	"pracuj":   {NewSiteScraper(builtin("pracuj"), nil, config.Source{}, nil, nil, nil).extractDataFromHTML, "https://www.pracuj.pl/praca/"},
+	"nofluff":  {NewSiteScraper(builtin("nofluff"), nil, config.Source{}, nil, nil, nil).extractDataFromHTML, "https://nofluffjobs.com/pl/job/"},
+	"justjoin": {NewSiteScraper(builtin("justjoin"), nil, config.Source{}, nil, nil, nil).extractDataFromHTML, "https://justjoin.it/job-offer/"},
+}
+
+func builtin(name string) Site {
+	site, ok := BuiltinSite(name)
+	if !ok {
+		panic("no builtin site " + name)
+	}
+	return site
+}
+
+// relative dates in the fixtures count from here
+var goldenNow = time.Date(2025, time.November, 17, 12, 0, 0, 0, time.UTC)
+
+func TestGolden(t *testing.T) {
+	now = func() time.Time { return goldenNow }
+	t.Cleanup(func() { now = time.Now })
+
+	for source, src := range goldenSources {
+		fixtures, err := filepath.Glob(filepath.Join("testdata", source, "*.html"))
+		require.NoError(t, err)
+		if len(fixtures) == 0 {
+			t.Errorf("no fixtures in testdata/%s", source)
+		}
+
+		for _, fixture := range fixtures {
+			name := strings.TrimSuffix(filepath.Base(fixture), ".html")
+			t.Run(source+"/"+name, func(t *testing.T) {
+				html, err := os.ReadFile(fixture)
+				require.NoError(t, err)
+
+				job, err, captcha := src.parser(string(html), src.baseURL+name)
+				require.NoError(t, err)
+				require.False(t, captcha, "fixture should not look like a captcha page")
+
+				// keep descriptions readable in the golden files
+				var buf bytes.Buffer
+				enc := json.NewEncoder(&buf)
+				enc.SetEscapeHTML(false)
+				enc.SetIndent("", "  ")
+				require.NoError(t, enc.Encode(job))
+				got := buf.Bytes()
+
+				goldenPath := strings.TrimSuffix(fixture, ".html") + ".golden.json"
+				if *update {
+					require.NoError(t, os.WriteFile(goldenPath, got, 0o644))
+					return
+				}
+
+				want, err := os.ReadFile(goldenPath)
+				require.NoError(t, err, "missing golden file, run with -update")
+				assert.JSONEq(t, string(want), string(got), "parser output changed for %s, check selectors or run with -update", fixture)
+			})
+		}
+	}
+}
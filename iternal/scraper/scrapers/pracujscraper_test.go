@@ -1,3 +1,97 @@
 package scrapers
 
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
 
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pracujOfferHTML = `<html><body>
+<h1 data-test="text-positionName">Golang Developer</h1>
+<h2 data-scroll-id="employer-name">ACME Sp. z o.o.O firmie</h2>
+<span data-test="item-technologies-expected">Go</span>
+<span data-test="item-technologies-optional">Docker</span>
+</body></html>`
+
+func TestPracujScrapeWithHTTPFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/broken":
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(pracujOfferHTML))
+	}))
+	defer srv.Close()
+
+	f := fetcher.NewHTTP(srv.Client(), "")
+	stats := scraper.NewStats()
+	p := NewSiteScraper(builtin("pracuj"), f, config.Source{Retries: 1}, []string{srv.URL + "/oferta", srv.URL + "/broken", srv.URL + "/missing"}, nil, stats)
+
+	q := make(chan scraper.JobOffer, 3)
+	require.NoError(t, p.Scrape(context.Background(), q))
+	close(q)
+
+	var jobs []scraper.JobOffer
+	for job := range q {
+		jobs = append(jobs, job)
+	}
+	require.Len(t, jobs, 3)
+	assert.Equal(t, "Golang Developer", jobs[0].Title)
+	assert.Equal(t, "ACME Sp. z o.o.", jobs[0].Company)
+	assert.Equal(t, []string{"Go", "Docker"}, jobs[0].Skills)
+	assert.Equal(t, "pracuj.pl", jobs[0].Source)
+	assert.False(t, jobs[0].Expired)
+	assert.NoError(t, jobs[0].Err)
+
+	// failures are reported for retry-failed, a removed offer is closed too
+	assert.Equal(t, srv.URL+"/broken", jobs[1].URL)
+	assert.False(t, jobs[1].Expired)
+	assert.Error(t, jobs[1].Err)
+	assert.Equal(t, srv.URL+"/missing", jobs[2].URL)
+	assert.True(t, jobs[2].Expired)
+	assert.Error(t, jobs[2].Err)
+	assert.Equal(t, []scraper.SourceStats{{Source: "pracuj.pl", Attempted: 3, FetchFailed: 1}}, stats.Sources())
+}
+
+func TestPracujScrapeCaptchaStrategies(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first visit of /guarded is a challenge page, the next ones pass
+		if r.URL.Path == "/guarded" && hits.Add(1) == 1 {
+			w.Write([]byte("<html><body><p>Verifying you are human</p></body></html>"))
+			return
+		}
+		w.Write([]byte(pracujOfferHTML))
+	}))
+	defer srv.Close()
+	urls := []string{srv.URL + "/guarded", srv.URL + "/oferta"}
+
+	captchas := scraper.NewCaptchas(scraper.RequeueCaptcha{}, 3)
+	p := NewSiteScraper(builtin("pracuj"), fetcher.NewHTTP(srv.Client(), ""), config.Source{Retries: 1}, urls, captchas, nil)
+	q := make(chan scraper.JobOffer, 2)
+	require.NoError(t, p.Scrape(context.Background(), q))
+	close(q)
+	var scraped []string
+	for job := range q {
+		scraped = append(scraped, job.URL)
+	}
+	assert.Equal(t, []string{srv.URL + "/oferta", srv.URL + "/guarded"}, scraped, "requeued url comes last")
+	assert.Equal(t, 1, captchas.Counts()[0].Requeued)
+
+	hits.Store(0)
+	p = NewSiteScraper(builtin("pracuj"), fetcher.NewHTTP(srv.Client(), ""), config.Source{Retries: 1}, urls, scraper.NewCaptchas(scraper.AbortCaptcha{}, 3), nil)
+	err := p.Scrape(context.Background(), make(chan scraper.JobOffer, 2))
+	assert.ErrorIs(t, err, scraper.ErrCaptchaAbort)
+}
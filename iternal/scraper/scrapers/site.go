@@ -0,0 +1,380 @@
+package scrapers
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// Site describes the offer pages of a job board with css selectors, SiteScraper scrapes
+// any board described by one. Definitions are yaml or json, see sites/ for the builtin boards.
+type Site struct {
+	// --source value, settings come from sources.<name> in the config
+	Name string `yaml:"name"`
+	// stored in job_offers.source
+	Source string `yaml:"source"`
+	// a page containing any of these is a captcha challenge, not an offer
+	CaptchaMarkers []string  `yaml:"captcha_markers"`
+	Expired        Expired   `yaml:"expired"`
+	Title          Field     `yaml:"title"`
+	Company        Field     `yaml:"company"`
+	CompanyURL     Field     `yaml:"company_url"`
+	Location       Location  `yaml:"location"`
+	Dates          Dates     `yaml:"dates"`
+	Description    []Section `yaml:"description"`
+	Skills         Field     `yaml:"skills"`
+	Salaries       Salaries  `yaml:"salaries"`
+}
+
+// Expired tells a taken down offer by its banner, a page with one of Phrases (lowercase) in an
+// element Selector matches. Banners and headings only, a description may quote anything.
+type Expired struct {
+	Selector string   `yaml:"selector"`
+	Phrases  []string `yaml:"phrases"`
+}
+
+// Field is the text of the elements Selector matches, every match cleaned up and filtered
+// on its own. A field without a selector reads nothing.
+type Field struct {
+	Selector string `yaml:"selector"`
+	// matches of Selector that also match Not are left out
+	Not string `yaml:"not"`
+	// only the first match counts
+	First bool `yaml:"first"`
+	// attribute read instead of the text
+	Attr string `yaml:"attr"`
+	// every line of a match is a value of its own
+	SplitLines bool `yaml:"split_lines"`
+	Cleanup    `yaml:",inline"`
+	// kept values contain one of Contains (when set) and none of NotContains, case insensitive
+	Contains    []string `yaml:"contains"`
+	NotContains []string `yaml:"not_contains"`
+	// separator of the values where the field is a single text
+	Join string `yaml:"join"`
+}
+
+// Cleanup rewrites a text in the order of its fields, the result is trimmed
+type Cleanup struct {
+	Replace []Replacement `yaml:"replace"`
+	Remove  []string      `yaml:"remove"`
+	// whitespace runs become one space
+	CollapseSpaces bool `yaml:"collapse_spaces"`
+	// cut from the end, "O firmie" glued to company names
+	TrimSuffix []string `yaml:"trim_suffix"`
+	// only the text after the first After is kept when it's there, "Gdańsk + 2 Locations"
+	After     string `yaml:"after"`
+	Lowercase bool   `yaml:"lowercase"`
+}
+
+type Replacement struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// Location is shown as its parts joined with ", ", empty and repeated pieces left out.
+// Place is parsed from the parts and hints together.
+type Location struct {
+	Parts []Field `yaml:"parts"`
+	Hints []Field `yaml:"hints"`
+	// a town ParseLocation doesn't know is taken from the end of the first part,
+	// an address like "Kapelanka 42A, Dębniki, Kraków"
+	CityFromAddress bool `yaml:"city_from_address"`
+}
+
+// Dates are texts tried in order until one parses, the json-ld of the page goes first
+type Dates struct {
+	Published []Field `yaml:"published"`
+	Expires   []Field `yaml:"expires"`
+	// texts sorted into publication and expiry by their wording, "Oferta ważna do 30.11.2025"
+	Mixed Field `yaml:"mixed"`
+}
+
+// Section is one part of the description html, matches are written in page order
+type Section struct {
+	Selector string `yaml:"selector"`
+	// "paragraph" writes the text of all matches as one <p>, "list" writes every match as
+	// its first h2/h3 in a Heading tag and its <li> items as a <ul>
+	As      string `yaml:"as"`
+	Heading string `yaml:"heading"`
+}
+
+const (
+	SectionParagraph = "paragraph"
+	SectionList      = "list"
+)
+
+// Salaries reads one salary per block matching Selector
+type Salaries struct {
+	Selector string `yaml:"selector"`
+	Not      string `yaml:"not"`
+	// texts of the block joined with Separator, the whole block text when there are none
+	Parts     []Field `yaml:"parts"`
+	Separator string  `yaml:"separator"`
+	Cleanup   `yaml:",inline"`
+	// the first rule with a word in the lowercase salary text decides its contract
+	Contracts []ContractRule `yaml:"contracts"`
+}
+
+const (
+	ContractEmployment = "employment"
+	ContractMandate    = "mandate"
+	ContractB2B        = "b2b"
+	// a salary offered on any contract fills all three
+	ContractAny = "any"
+)
+
+type ContractRule struct {
+	Contains []string `yaml:"contains"`
+	Contract string   `yaml:"contract"`
+}
+
+//go:embed sites/*.yaml
+var builtinFS embed.FS
+
+var siteNameRe = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ParseSite reads a yaml or json site definition, unknown keys and broken selectors are errors
+func ParseSite(data []byte) (Site, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var site Site
+	if err := dec.Decode(&site); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Site{}, errors.New("empty site definition")
+		}
+		return Site{}, err
+	}
+	return site, site.validate()
+}
+
+// BuiltinSites are the boards this binary ships definitions for
+func BuiltinSites() []Site {
+	return slices.Clone(builtinSites())
+}
+
+// the embedded definitions never change, they are parsed on first use only
+var builtinSites = sync.OnceValue(func() []Site {
+	names, err := builtinFS.ReadDir("sites")
+	if err != nil {
+		panic(err)
+	}
+	sites := make([]Site, 0, len(names))
+	for _, e := range names {
+		data, err := builtinFS.ReadFile("sites/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		site, err := ParseSite(data)
+		if err != nil {
+			panic(fmt.Sprintf("builtin site %s: %v", e.Name(), err))
+		}
+		sites = append(sites, site)
+	}
+	return sites
+})
+
+// BuiltinSite is the shipped definition of the named board
+func BuiltinSite(name string) (Site, bool) {
+	sites := builtinSites()
+	i := slices.IndexFunc(sites, func(s Site) bool { return s.Name == name })
+	if i < 0 {
+		return Site{}, false
+	}
+	return sites[i], true
+}
+
+// LoadSites reads every *.yaml, *.yml and *.json site definition in dir
+func LoadSites(dir string) ([]Site, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var sites []Site
+	seen := map[string]string{}
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		site, err := ParseSite(data)
+		if err != nil {
+			return nil, fmt.Errorf("site %s: %w", e.Name(), err)
+		}
+		if other, ok := seen[site.Name]; ok {
+			return nil, fmt.Errorf("sites %s and %s are both named %q", other, e.Name(), site.Name)
+		}
+		seen[site.Name] = e.Name()
+		sites = append(sites, site)
+	}
+	return sites, nil
+}
+
+// reports every problem at once like config.Validate
+func (s Site) validate() error {
+	var errs []error
+	if !siteNameRe.MatchString(s.Name) {
+		errs = append(errs, fmt.Errorf("name: must be lowercase letters, digits, - or _, got %q", s.Name))
+	}
+	if strings.TrimSpace(s.Source) == "" {
+		errs = append(errs, errors.New("source: must not be empty"))
+	}
+	if s.Title.Selector == "" {
+		errs = append(errs, errors.New("title.selector: must not be empty"))
+	}
+	selector := func(path, sel string) {
+		if sel == "" {
+			return
+		}
+		if _, err := cascadia.ParseGroup(sel); err != nil {
+			errs = append(errs, fmt.Errorf("%s: bad selector %q: %w", path, sel, err))
+		}
+	}
+	field := func(path string, f Field) {
+		selector(path+".selector", f.Selector)
+		selector(path+".not", f.Not)
+	}
+	selector("expired.selector", s.Expired.Selector)
+	if (s.Expired.Selector == "") != (len(s.Expired.Phrases) == 0) {
+		errs = append(errs, errors.New("expired: needs both a selector and phrases"))
+	}
+	field("title", s.Title)
+	field("company", s.Company)
+	field("company_url", s.CompanyURL)
+	for i, f := range s.Location.Parts {
+		field(fmt.Sprintf("location.parts[%d]", i), f)
+	}
+	for i, f := range s.Location.Hints {
+		field(fmt.Sprintf("location.hints[%d]", i), f)
+	}
+	for i, f := range s.Dates.Published {
+		field(fmt.Sprintf("dates.published[%d]", i), f)
+	}
+	for i, f := range s.Dates.Expires {
+		field(fmt.Sprintf("dates.expires[%d]", i), f)
+	}
+	field("dates.mixed", s.Dates.Mixed)
+	for i, sec := range s.Description {
+		path := fmt.Sprintf("description[%d]", i)
+		if sec.Selector == "" {
+			errs = append(errs, fmt.Errorf("%s.selector: must not be empty", path))
+		}
+		selector(path+".selector", sec.Selector)
+		switch sec.As {
+		case "", SectionParagraph, SectionList:
+		default:
+			errs = append(errs, fmt.Errorf("%s.as: must be %q or %q, got %q", path, SectionParagraph, SectionList, sec.As))
+		}
+		switch sec.Heading {
+		case "", "h2", "h3", "h4":
+		default:
+			errs = append(errs, fmt.Errorf("%s.heading: must be h2, h3 or h4, got %q", path, sec.Heading))
+		}
+	}
+	field("skills", s.Skills)
+	selector("salaries.selector", s.Salaries.Selector)
+	selector("salaries.not", s.Salaries.Not)
+	for i, f := range s.Salaries.Parts {
+		field(fmt.Sprintf("salaries.parts[%d]", i), f)
+	}
+	if s.Salaries.Selector != "" && len(s.Salaries.Contracts) == 0 {
+		errs = append(errs, errors.New("salaries.contracts: salaries need rules to tell their contract"))
+	}
+	for i, r := range s.Salaries.Contracts {
+		switch r.Contract {
+		case ContractEmployment, ContractMandate, ContractB2B, ContractAny:
+		default:
+			errs = append(errs, fmt.Errorf("salaries.contracts[%d].contract: must be %s, %s, %s or %s, got %q",
+				i, ContractEmployment, ContractMandate, ContractB2B, ContractAny, r.Contract))
+		}
+		if len(r.Contains) == 0 {
+			errs = append(errs, fmt.Errorf("salaries.contracts[%d].contains: must not be empty", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Cleanup) apply(text string) string {
+	for _, r := range c.Replace {
+		text = strings.ReplaceAll(text, r.From, r.To)
+	}
+	for _, r := range c.Remove {
+		text = strings.ReplaceAll(text, r, "")
+	}
+	if c.CollapseSpaces {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	text = strings.TrimSpace(text)
+	for _, suffix := range c.TrimSuffix {
+		text = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+	}
+	if _, after, ok := strings.Cut(text, c.After); ok && c.After != "" {
+		text = strings.TrimSpace(after)
+	}
+	if c.Lowercase {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+func containsAny(text string, words []string) bool {
+	text = strings.ToLower(text)
+	for _, w := range words {
+		if strings.Contains(text, strings.ToLower(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+// values of the field inside root, empty ones left out
+func (f Field) values(root *goquery.Selection) []string {
+	if f.Selector == "" {
+		return nil
+	}
+	matches := root.Find(f.Selector)
+	if f.Not != "" {
+		matches = matches.Not(f.Not)
+	}
+	if f.First {
+		matches = matches.First()
+	}
+	var values []string
+	matches.Each(func(_ int, s *goquery.Selection) {
+		raw := s.Text()
+		if f.Attr != "" {
+			raw = s.AttrOr(f.Attr, "")
+		}
+		texts := []string{raw}
+		if f.SplitLines {
+			texts = strings.Split(raw, "\n")
+		}
+		for _, text := range texts {
+			text = f.apply(text)
+			if text == "" || (len(f.Contains) > 0 && !containsAny(text, f.Contains)) || containsAny(text, f.NotContains) {
+				continue
+			}
+			values = append(values, text)
+		}
+	})
+	return values
+}
+
+func (f Field) text(root *goquery.Selection) string {
+	return strings.Join(f.values(root), f.Join)
+}
@@ -0,0 +1,260 @@
+package scrapers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// SiteScraper scrapes the offer pages of any board its Site describes,
+// pages come from the injected fetcher, delays from config
+type SiteScraper struct {
+	site    Site
+	fetcher fetcher.Fetcher
+	cfg     config.Source
+	urls    []string
+	captcha scraper.CaptchaStrategy
+	stats   *scraper.Stats
+}
+
+func NewSiteScraper(site Site, f fetcher.Fetcher, cfg config.Source, urls []string, captcha scraper.CaptchaStrategy, stats *scraper.Stats) *SiteScraper {
+	return &SiteScraper{
+		site:    site,
+		fetcher: f,
+		cfg:     cfg,
+		urls:    urls,
+		captcha: captcha,
+		stats:   stats,
+	}
+}
+
+func (p *SiteScraper) Source() string {
+	return p.site.Source
+}
+
+// extracting data from string html with the selectors of the site
+func (p *SiteScraper) extractDataFromHTML(html string, url string) (scraper.JobOffer, error, bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		log.Printf("goquery parse error: %v", err)
+		return scraper.JobOffer{}, err, false
+	}
+
+	for _, marker := range p.site.CaptchaMarkers {
+		if strings.Contains(html, marker) {
+			return scraper.JobOffer{}, nil, true
+		}
+	}
+
+	if p.site.Expired.matches(doc) {
+		return expiredOffer(url, p.Source()), nil, false
+	}
+
+	site := p.site
+	root := doc.Selection
+	var job scraper.JobOffer
+	job.URL = url
+	job.Source = p.Source()
+	job.Title = site.Title.text(root)
+	job.Company = site.Company.text(root)
+	if values := site.CompanyURL.values(root); len(values) > 0 {
+		job.CompanyURL = absoluteURL(url, values[0])
+	}
+
+	job.Location, job.Place = site.Location.read(root)
+
+	var published, expires []string
+	for _, f := range site.Dates.Published {
+		published = append(published, f.values(root)...)
+	}
+	for _, f := range site.Dates.Expires {
+		expires = append(expires, f.values(root)...)
+	}
+	mixedPublished, mixedExpires := dateTexts(site.Dates.Mixed.values(root))
+	job.PublishedAt, job.ExpiresAt = offerDates(doc, append(published, mixedPublished...), append(expires, mixedExpires...))
+
+	var htmlBuilder strings.Builder
+	for _, section := range site.Description {
+		section.write(&htmlBuilder, root)
+	}
+	job.Description = htmlBuilder.String()
+
+	job.Skills = site.Skills.values(root)
+	site.Salaries.read(root, &job)
+
+	return job, nil, false
+}
+
+func (l Location) read(root *goquery.Selection) (string, scraper.Location) {
+	var pieces, texts []string
+	seen := map[string]bool{}
+	for _, f := range l.Parts {
+		part := f.text(root)
+		texts = append(texts, part)
+		for _, piece := range strings.Split(part, ",") {
+			piece = strings.TrimSpace(piece)
+			if piece != "" && !seen[piece] {
+				seen[piece] = true
+				pieces = append(pieces, piece)
+			}
+		}
+	}
+	for _, f := range l.Hints {
+		texts = append(texts, f.values(root)...)
+	}
+
+	place := scraper.ParseLocation(texts...)
+	if l.CityFromAddress && len(place.Cities) == 0 && len(texts) > 0 && texts[0] != "" {
+		// unknown town, the address ends with the city
+		parts := strings.Split(texts[0], ",")
+		if city := strings.TrimSpace(parts[len(parts)-1]); city != "" && !strings.ContainsAny(city, "0123456789") {
+			place.Cities = []string{city}
+			place.Country = "PL"
+			if place.WorkMode == "" {
+				place.WorkMode = scraper.WorkModeOnsite
+			}
+		}
+	}
+	return strings.Join(pieces, ", "), place
+}
+
+func (s Section) write(b *strings.Builder, root *goquery.Selection) {
+	matches := root.Find(s.Selector)
+	if s.As != SectionList {
+		if text := strings.TrimSpace(matches.Text()); text != "" {
+			b.WriteString("<p>" + text + "</p>\n")
+		}
+		return
+	}
+	matches.Each(func(_ int, m *goquery.Selection) {
+		heading := strings.TrimSpace(m.Find("h2, h3").First().Text())
+		if heading != "" {
+			tag := s.Heading
+			if tag == "" {
+				tag = "h2"
+			}
+			b.WriteString("<" + tag + ">" + heading + "</" + tag + ">\n")
+		}
+
+		b.WriteString("<ul>\n")
+		m.Find("li").Each(func(_ int, li *goquery.Selection) {
+			if text := strings.TrimSpace(li.Text()); text != "" {
+				b.WriteString("<li>" + text + "</li>\n")
+			}
+		})
+		b.WriteString("</ul>\n")
+	})
+}
+
+func (s Salaries) read(root *goquery.Selection, job *scraper.JobOffer) {
+	if s.Selector == "" {
+		return
+	}
+	blocks := root.Find(s.Selector)
+	if s.Not != "" {
+		blocks = blocks.Not(s.Not)
+	}
+	blocks.Each(func(_ int, block *goquery.Selection) {
+		var text string
+		if len(s.Parts) == 0 {
+			text = block.Text()
+		} else {
+			var parts []string
+			for _, f := range s.Parts {
+				if part := f.text(block); part != "" {
+					parts = append(parts, part)
+				}
+			}
+			text = strings.Join(parts, s.Separator)
+		}
+		text = s.apply(text)
+		if text == "" {
+			return
+		}
+
+		for _, rule := range s.Contracts {
+			if !containsAny(text, rule.Contains) {
+				continue
+			}
+			switch rule.Contract {
+			case ContractEmployment:
+				job.SalaryEmployment = text
+			case ContractMandate:
+				job.SalaryContract = text
+			case ContractB2B:
+				job.SalaryB2B = text
+			case ContractAny:
+				job.SalaryEmployment, job.SalaryContract, job.SalaryB2B = text, text, text
+			}
+			return
+		}
+	})
+}
+
+// main func for scraping
+func (p *SiteScraper) Scrape(ctx context.Context, q chan<- scraper.JobOffer) error {
+	for i := 0; i < len(p.urls); i++ {
+		url := p.urls[i]
+		p.stats.Attempt(p.Source(), url)
+		var job scraper.JobOffer
+		page, err := p.fetcher.Fetch(ctx, url)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !removedStatus(err) {
+				log.Printf("Fetch error: %v", err)
+				p.stats.Count(p.Source(), scraper.FetchFailed)
+			}
+			// the collector files it for retry-failed, removed pages as permanent failures
+			job = failedOffer(url, p.Source(), err)
+		} else {
+			var captchaAppeared bool
+			job, err, captchaAppeared = p.extractDataFromHTML(page.HTML, url)
+			// nothing matched the selectors, the page layout probably changed
+			if err == nil && !captchaAppeared && !job.Expired && job.Title == "" {
+				err = errors.New("no title found")
+			}
+			switch {
+			// the fetcher already backed off, the strategy decides when to try again
+			case captchaAppeared:
+				p.stats.Count(p.Source(), scraper.CaptchaHit)
+				action, err := p.captcha.Handle(ctx, p.Source(), url)
+				if err != nil {
+					return err
+				}
+				switch action {
+				case scraper.CaptchaRetry:
+					i--
+					continue
+				case scraper.CaptchaRequeue:
+					p.urls = append(p.urls, url)
+					continue
+				}
+				job = failedOffer(url, p.Source(), errCaptcha)
+			case err != nil:
+				log.Printf("Parse error for %s: %v", url, err)
+				p.stats.Count(p.Source(), scraper.ParseFailed)
+				job = failedOffer(url, p.Source(), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case q <- job:
+		}
+
+		if job.Err == nil {
+			log.Printf("Scraped %d: %s", i+1, url)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package scrapers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const boardSite = `
+name: board
+source: board.example
+captcha_markers: [Just a moment]
+expired: {selector: .banner, phrases: [offer has ended]}
+title: {selector: h1.title}
+company: {selector: .employer, trim_suffix: [See profile]}
+company_url: {selector: .employer a, attr: href}
+location:
+  parts:
+    - {selector: .city}
+    - {selector: .mode, lowercase: true}
+dates:
+  mixed: {selector: .dates li}
+description:
+  - {selector: .about}
+  - {selector: .duties, as: list, heading: h3}
+skills: {selector: .tags, split_lines: true, remove: [Tags]}
+salaries:
+  selector: .salary
+  parts: [{selector: .amount}, {selector: .kind, lowercase: true}]
+  separator: ", "
+  contracts:
+    - {contains: [permanent], contract: employment}
+    - {contains: [any contract], contract: any}
+`
+
+const boardOfferHTML = `<html><body>
+<h1 class="title"> Go Developer </h1>
+<div class="employer"><a href="/companies/initech">Initech</a> See profile</div>
+<span class="city">Poznań</span><span class="mode">Hybrid</span>
+<ul class="dates"><li>Published 2 days ago</li><li>Valid until 30.11.2025</li></ul>
+<div class="about">We build payment terminals.</div>
+<section class="duties"><h2>Your duties</h2><ul><li>writing Go</li><li> </li></ul></section>
+<div class="tags">Tags
+Go
+gRPC
+</div>
+<div class="salary"><span class="amount">15 000 PLN</span><span class="kind">Permanent</span></div>
+</body></html>`
+
+func TestSiteScraperFromDefinition(t *testing.T) {
+	now = func() time.Time { return goldenNow }
+	t.Cleanup(func() { now = time.Now })
+
+	site, err := ParseSite([]byte(boardSite))
+	require.NoError(t, err)
+	p := NewSiteScraper(site, nil, config.Source{}, nil, nil, nil)
+	assert.Equal(t, "board.example", p.Source())
+
+	job, err, captcha := p.extractDataFromHTML(boardOfferHTML, "https://board.example/offers/1")
+	require.NoError(t, err)
+	require.False(t, captcha)
+	assert.Equal(t, "Go Developer", job.Title)
+	assert.Equal(t, "Initech", job.Company)
+	assert.Equal(t, "https://board.example/companies/initech", job.CompanyURL)
+	assert.Equal(t, "Poznań, hybrid", job.Location)
+	assert.Equal(t, []string{"Poznań"}, job.Place.Cities)
+	assert.Equal(t, scraper.WorkModeHybrid, job.Place.WorkMode)
+	require.NotNil(t, job.PublishedAt)
+	assert.Equal(t, time.Date(2025, time.November, 15, 0, 0, 0, 0, time.UTC), *job.PublishedAt)
+	require.NotNil(t, job.ExpiresAt)
+	assert.Equal(t, time.Date(2025, time.November, 30, 0, 0, 0, 0, time.UTC), *job.ExpiresAt)
+	assert.Equal(t, "<p>We build payment terminals.</p>\n<h3>Your duties</h3>\n<ul>\n<li>writing Go</li>\n</ul>\n", job.Description)
+	assert.Equal(t, []string{"Go", "gRPC"}, job.Skills)
+	assert.Equal(t, "15 000 PLN, permanent", job.SalaryEmployment)
+	assert.Empty(t, job.SalaryB2B)
+
+	_, _, captcha = p.extractDataFromHTML("<html><body>Just a moment...</body></html>", "https://board.example/offers/2")
+	assert.True(t, captcha)
+	job, err, _ = p.extractDataFromHTML(`<div class="banner">This offer has  ended</div>`, "https://board.example/offers/2")
+	require.NoError(t, err)
+	assert.True(t, job.Expired)
+
+	// json is yaml too
+	site, err = ParseSite([]byte(`{"name": "board", "source": "board.example", "title": {"selector": "h1.title"},
+		"salaries": {"selector": ".salary", "contracts": [{"contains": ["any contract"], "contract": "any"}]}}`))
+	require.NoError(t, err)
+	job, err, _ = NewSiteScraper(site, nil, config.Source{}, nil, nil, nil).extractDataFromHTML(
+		`<h1 class="title">Tester</h1><div class="salary">9 000 PLN any contract</div>`, "https://board.example/offers/3")
+	require.NoError(t, err)
+	assert.Equal(t, "9 000 PLN any contract", job.SalaryEmployment)
+	assert.Equal(t, job.SalaryEmployment, job.SalaryB2B)
+	assert.Equal(t, job.SalaryEmployment, job.SalaryContract)
+}
+
+func TestParseSiteRejectsBadDefinitions(t *testing.T) {
+	for name, def := range map[string]string{
+		"empty":            ``,
+		"no title":         "name: board\nsource: board.example\n",
+		"no source":        "name: board\ntitle: {selector: h1}\n",
+		"bad name":         "name: Board Example\nsource: board.example\ntitle: {selector: h1}\n",
+		"unknown key":      "name: board\nsource: board.example\ntitle: {selector: h1, trim: true}\n",
+		"broken selector":  "name: board\nsource: board.example\ntitle: {selector: 'h1['}\n",
+		"unknown contract": "name: board\nsource: board.example\ntitle: {selector: h1}\nsalaries: {selector: .salary, contracts: [{contains: [b2b], contract: freelance}]}\n",
+		"no contracts":     "name: board\nsource: board.example\ntitle: {selector: h1}\nsalaries: {selector: .salary}\n",
+		"bad section":      "name: board\nsource: board.example\ntitle: {selector: h1}\ndescription: [{selector: .about, as: table}]\n",
+		"expired phrases":  "name: board\nsource: board.example\ntitle: {selector: h1}\nexpired: {selector: .banner}\n",
+	} {
+		_, err := ParseSite([]byte(def))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoadSites(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "board.yaml"), []byte(boardSite), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a site"), 0o644))
+	sites, err := LoadSites(dir)
+	require.NoError(t, err)
+	require.Len(t, sites, 1)
+	assert.Equal(t, "board", sites[0].Name)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "board.json"), []byte(`{"name": "board", "source": "other.example", "title": {"selector": "h1"}}`), 0o644))
+	_, err = LoadSites(dir)
+	assert.ErrorContains(t, err, `both named "board"`)
+}
+
+func TestBuiltinSites(t *testing.T) {
+	var names []string
+	for _, site := range BuiltinSites() {
+		names = append(names, site.Name)
+	}
+	assert.ElementsMatch(t, []string{"pracuj", "nofluff", "justjoin"}, names)
+}
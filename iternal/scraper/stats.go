@@ -0,0 +1,108 @@
+package scraper
+
+import (
+	"sort"
+	"sync"
+)
+
+// Outcome is what happened to one url of a scrape
+type Outcome int
+
+const (
+	FetchFailed Outcome = iota
+	ParseFailed
+	CaptchaHit
+	Saved
+	Closed
+	SaveFailed
+)
+
+// SourceStats counts what happened to the urls of one source during a scrape run
+type SourceStats struct {
+	Source      string `json:"source"`
+	Attempted   int    `json:"attempted"`
+	Saved       int    `json:"saved"`
+	Closed      int    `json:"closed"`
+	FetchFailed int    `json:"fetch_failed"`
+	ParseFailed int    `json:"parse_failed"`
+	SaveFailed  int    `json:"save_failed"`
+	Captchas    int    `json:"captchas"`
+}
+
+// Stats is shared by the scrapers and the collector of one run, a nil Stats counts nothing
+type Stats struct {
+	mu        sync.Mutex
+	sources   map[string]*SourceStats
+	attempted map[string]map[string]bool
+}
+
+// NewStats starts counting, the given sources show up even when they had nothing to do
+func NewStats(sources ...string) *Stats {
+	s := &Stats{sources: map[string]*SourceStats{}, attempted: map[string]map[string]bool{}}
+	for _, source := range sources {
+		s.source(source)
+	}
+	return s
+}
+
+// Attempt records a url the scraper started on, retries and requeues count once
+func (s *Stats) Attempt(source, url string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.source(source)
+	if !s.attempted[source][url] {
+		s.attempted[source][url] = true
+		stats.Attempted++
+	}
+}
+
+func (s *Stats) Count(source string, outcome Outcome) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.source(source)
+	switch outcome {
+	case FetchFailed:
+		stats.FetchFailed++
+	case ParseFailed:
+		stats.ParseFailed++
+	case CaptchaHit:
+		stats.Captchas++
+	case Saved:
+		stats.Saved++
+	case Closed:
+		stats.Closed++
+	case SaveFailed:
+		stats.SaveFailed++
+	}
+}
+
+func (s *Stats) source(source string) *SourceStats {
+	stats, ok := s.sources[source]
+	if !ok {
+		stats = &SourceStats{Source: source}
+		s.sources[source] = stats
+		s.attempted[source] = map[string]bool{}
+	}
+	return stats
+}
+
+// Sources returns a snapshot of every source by name
+func (s *Stats) Sources() []SourceStats {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sources := make([]SourceStats, 0, len(s.sources))
+	for _, stats := range s.sources {
+		sources = append(sources, *stats)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Source < sources[j].Source })
+	return sources
+}
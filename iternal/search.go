@@ -0,0 +1,144 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+// ErrSearchUnavailable is returned when the binary was built without the sqlite_fts5 tag
+var ErrSearchUnavailable = errors.New("full-text search needs a build with -tags sqlite_fts5")
+
+// SearchResult is one matching offer, Snippet marks matched terms with [ and ]
+type SearchResult struct {
+	Offer   scraper.JobOffer `json:"offer"`
+	Snippet string           `json:"snippet"`
+	Rank    float64          `json:"rank"`
+}
+
+// SearchJobOffers returns offers matching query best first, query is plain words
+// that must all match unless raw is set, then it is passed to fts5 as is
+func SearchJobOffers(ctx context.Context, db *sql.DB, query string, raw bool, limit int) ([]SearchResult, error) {
+	if !ftsEnabled {
+		return nil, ErrSearchUnavailable
+	}
+	if !raw {
+		query = ftsQuery(query)
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("empty search query")
+	}
+
+	rows, err := database.New(db).SearchJobOffers(ctx, database.SearchJobOffersParams{Query: query, Limit: int64(limit)})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, SearchResult{
+			Offer:   jobOfferFromRow(row.JobOffer),
+			Snippet: row.Snippet,
+			Rank:    row.Rank,
+		})
+	}
+	return results, nil
+}
+
+// quotes every term so input like "c++" or "node.js" is not read as fts5 syntax,
+// a trailing * keeps working as prefix match
+func ftsQuery(input string) string {
+	var terms []string
+	for _, term := range strings.Fields(input) {
+		prefix := strings.HasSuffix(term, "*")
+		term = strings.Trim(term, `"*`)
+		if term == "" {
+			continue
+		}
+		quoted := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if prefix {
+			quoted += "*"
+		}
+		terms = append(terms, quoted)
+	}
+	return strings.Join(terms, " ")
+}
+
+// replaces the search row of an offer, description html is indexed as plain text
+func indexJobOffer(ctx context.Context, q *database.Queries, offerID string, job scraper.JobOffer) error {
+	if !ftsEnabled {
+		return markSearchStale(ctx, q)
+	}
+	if err := q.DeleteJobOfferSearch(ctx, offerID); err != nil {
+		return err
+	}
+	return q.InsertJobOfferSearch(ctx, database.InsertJobOfferSearchParams{
+		JobOfferID:  offerID,
+		Title:       job.Title,
+		Company:     job.Company,
+		Description: htmlToText(job.Description),
+		Skills:      strings.Join(job.Skills, ", "),
+	})
+}
+
+func htmlToText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// a build without fts5 can't keep an index made by one up to date, the next fts5 build rebuilds it
+func markSearchStale(ctx context.Context, q *database.Queries) error {
+	marked, err := q.MarkSearchIndexStale(ctx)
+	if err == nil && marked > 0 {
+		log.Printf("Search index is out of date, the next build with -tags sqlite_fts5 rebuilds it")
+	}
+	return err
+}
+
+// RebuildSearchIndex reindexes every offer when the index is flagged stale, and returns how many
+// it indexed. A build without fts5 leaves the index alone.
+func RebuildSearchIndex(ctx context.Context, db *sql.DB) (int, error) {
+	if !ftsEnabled {
+		return 0, nil
+	}
+	q := database.New(db)
+	stale, err := q.GetSearchIndexStale(ctx)
+	if err != nil || !stale {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	querier := q.WithTx(tx)
+
+	if err := querier.ClearJobOffersSearch(ctx); err != nil {
+		return 0, err
+	}
+	rows, err := querier.ListJobOffersForSearch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		job := scraper.JobOffer{Title: row.Title, Company: row.Company.String, Description: row.Description.String}
+		_ = json.Unmarshal([]byte(row.Skills.String), &job.Skills)
+		if err := indexJobOffer(ctx, querier, row.ID, job); err != nil {
+			return 0, err
+		}
+	}
+	if err := querier.SetSearchIndexStale(ctx, false); err != nil {
+		return 0, err
+	}
+	return len(rows), tx.Commit()
+}
@@ -0,0 +1,117 @@
+package iternal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFtsQuery(t *testing.T) {
+	assert.Equal(t, `"c++" "node.js"`, ftsQuery("c++  node.js"))
+	assert.Equal(t, `"kube"*`, ftsQuery("kube*"))
+	assert.Equal(t, `"go" "OR" "a""b"`, ftsQuery(`"go OR a"b`))
+	assert.Equal(t, "", ftsQuery(" * "))
+}
+
+func TestSearchJobOffers(t *testing.T) {
+	if !ftsEnabled {
+		t.Skip("built without sqlite_fts5")
+	}
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	jobs := []scraper.JobOffer{
+		{
+			Title:       "Senior Go Developer",
+			Company:     "Acme",
+			URL:         "https://www.pracuj.pl/praca/senior-go,oferta,1",
+			Source:      "pracuj.pl",
+			Description: "<ul><li>Budujemy mikroserwisy w <b>Kubernetes</b></li></ul>",
+			Skills:      []string{"Go", "PostgreSQL"},
+		},
+		{
+			Title:       "Frontend Developer",
+			Company:     "Kubernetes Fans",
+			URL:         "https://justjoin.it/job-offer/frontend",
+			Source:      "justjoin.it",
+			Description: "<p>React i TypeScript</p>",
+			Skills:      []string{"React"},
+		},
+	}
+	for _, job := range jobs {
+		require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	}
+
+	results, err := SearchJobOffers(ctx, db, "kubernetes", false, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Kubernetes Fans", results[0].Offer.Company, "company weighs more than description")
+	assert.Contains(t, results[1].Snippet, "[Kubernetes]")
+	assert.NotContains(t, results[1].Snippet, "<li>", "html is stripped")
+
+	// rescrape replaces the indexed text instead of adding a second row
+	jobs[0].Description = "<p>Tylko Go</p>"
+	require.NoError(t, saveJobOffer(ctx, db, jobs[0], nil))
+	results, err = SearchJobOffers(ctx, db, "kubernetes", false, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = SearchJobOffers(ctx, db, "skills:react OR title:go", true, 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestRebuildSearchIndex(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	q := database.New(db)
+	job := scraper.JobOffer{
+		Title:       "Senior Go Developer",
+		Company:     "Acme",
+		URL:         "https://www.pracuj.pl/praca/senior-go,oferta,1",
+		Source:      "pracuj.pl",
+		Description: "<ul><li>Budujemy mikroserwisy w <b>Kubernetes</b></li></ul>",
+		Skills:      []string{"Go", "PostgreSQL"},
+	}
+
+	if !ftsEnabled {
+		// stands in for the index an fts5 build created
+		_, err := db.ExecContext(ctx, "CREATE TABLE job_offers_fts (job_offer_id TEXT)")
+		require.NoError(t, err)
+		require.NoError(t, q.SetSearchIndexStale(ctx, false))
+		require.NoError(t, saveJobOffer(ctx, db, job, nil))
+		stale, err := q.GetSearchIndexStale(ctx)
+		require.NoError(t, err)
+		assert.True(t, stale, "the offer is missing from the index")
+		return
+	}
+
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	offer, err := q.GetJobOfferByUrl(ctx, urlNormalizer(job.URL))
+	require.NoError(t, err)
+	// what the sql backfill used to index
+	require.NoError(t, q.DeleteJobOfferSearch(ctx, offer.ID))
+	require.NoError(t, q.InsertJobOfferSearch(ctx, database.InsertJobOfferSearchParams{
+		JobOfferID: offer.ID, Title: job.Title, Company: job.Company, Description: job.Description, Skills: `["Go","PostgreSQL"]`,
+	}))
+	require.NoError(t, q.SetSearchIndexStale(ctx, true))
+
+	indexed, err := RebuildSearchIndex(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, indexed)
+	results, err := SearchJobOffers(ctx, db, "kubernetes", false, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.NotContains(t, results[0].Snippet, "<li>")
+	results, err = SearchJobOffers(ctx, db, "skills:postgresql", true, 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	indexed, err = RebuildSearchIndex(ctx, db)
+	require.NoError(t, err)
+	assert.Zero(t, indexed, "an index up to date is left alone")
+}
@@ -0,0 +1,357 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+	defaultTopSize  = 20
+)
+
+type apiSalary struct {
+	Contract   string  `json:"contract"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Currency   string  `json:"currency,omitempty"`
+	Period     string  `json:"period"`
+	Gross      *bool   `json:"gross"`
+	MonthlyMin float64 `json:"monthly_min"`
+	MonthlyMax float64 `json:"monthly_max"`
+}
+
+type apiOffer struct {
+	scraper.JobOffer
+	Salaries []apiSalary `json:"salaries"`
+	// the offers of the same job on every board, this one included
+	Postings []PostingLink `json:"postings"`
+}
+
+type offerPage struct {
+	Offers []apiOffer `json:"offers"`
+	Total  int64      `json:"total"`
+	Limit  int64      `json:"limit"`
+	Offset int64      `json:"offset"`
+}
+
+type salaryStats struct {
+	Contract      string  `json:"contract"`
+	Currency      string  `json:"currency,omitempty"`
+	Offers        int64   `json:"offers"`
+	AvgMonthlyMin float64 `json:"avg_monthly_min"`
+	AvgMonthlyMax float64 `json:"avg_monthly_max"`
+	MinMonthly    float64 `json:"min_monthly"`
+	MaxMonthly    float64 `json:"max_monthly"`
+}
+
+type apiServer struct {
+	q    *database.Queries
+	norm *skills.Normalizer
+}
+
+// NewServer returns the read-only json api over the offers database,
+// skill filters go through norm so aliases like "golang" find "Go"
+func NewServer(db *sql.DB, norm *skills.Normalizer) http.Handler {
+	s := &apiServer{q: database.New(db), norm: norm}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/offers", s.listOffers)
+	mux.HandleFunc("GET /api/offers/{id}", s.getOffer)
+	mux.HandleFunc("GET /api/offers/{id}/history", s.offerHistory)
+	mux.HandleFunc("GET /api/stats/sources", s.sourceStats)
+	mux.HandleFunc("GET /api/stats/skills", s.skillStats)
+	mux.HandleFunc("GET /api/stats/cities", s.cityStats)
+	mux.HandleFunc("GET /api/stats/salaries", s.salaryStats)
+	return mux
+}
+
+// badRequest errors are shown to the client, anything else is logged and hidden
+type badRequest struct{ msg string }
+
+func (e badRequest) Error() string { return e.msg }
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error %s in writing response", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var bad badRequest
+	switch {
+	case errors.As(err, &bad):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": bad.msg})
+	case errors.Is(err, sql.ErrNoRows):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		log.Printf("Error %s in %s %s", err, r.Method, r.URL)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+	}
+}
+
+func queryString(r *http.Request, name string) sql.NullString {
+	v := r.URL.Query().Get(name)
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+func queryFloat(r *http.Request, name string) (sql.NullFloat64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return sql.NullFloat64{}, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return sql.NullFloat64{}, badRequest{fmt.Sprintf("%s: not a number: %q", name, v)}
+	}
+	return sql.NullFloat64{Float64: f, Valid: true}, nil
+}
+
+func queryBool(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest{fmt.Sprintf("%s: not a boolean: %q", name, v)}
+	}
+	return b, nil
+}
+
+func queryInt(r *http.Request, name string, def, min, max int64) (int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < min || n > max {
+		return 0, badRequest{fmt.Sprintf("%s: must be a number between %d and %d, got %q", name, min, max, v)}
+	}
+	return n, nil
+}
+
+// adds cities, structured salaries and the job's other postings to a stored row
+func (s *apiServer) offer(ctx context.Context, row database.JobOffer) (apiOffer, error) {
+	o := apiOffer{JobOffer: jobOfferFromRow(row), Salaries: []apiSalary{}}
+	var err error
+	if o.Place.Cities, err = s.q.ListJobOfferCities(ctx, row.ID); err != nil {
+		return o, err
+	}
+	if o.Postings, err = postingLinks(ctx, s.q, row); err != nil {
+		return o, err
+	}
+	salaries, err := s.q.ListJobOfferSalaries(ctx, row.ID)
+	if err != nil {
+		return o, err
+	}
+	for _, sal := range salaries {
+		a := apiSalary{
+			Contract:   sal.ContractType,
+			Min:        sal.MinAmount,
+			Max:        sal.MaxAmount,
+			Currency:   sal.Currency.String,
+			Period:     sal.Period,
+			MonthlyMin: sal.MonthlyMin,
+			MonthlyMax: sal.MonthlyMax,
+		}
+		if sal.Gross.Valid {
+			a.Gross = &sal.Gross.Bool
+		}
+		o.Salaries = append(o.Salaries, a)
+	}
+	return o, nil
+}
+
+// GET /api/offers?source=&company=&skill=&city=&work_mode=&currency=&min_salary=&max_salary=&include_closed=&group=&limit=&offset=
+// salary bounds are monthly amounts, an offer matches when any of its salaries overlaps the range,
+// closed offers are left out unless include_closed is true, group=true returns one offer per job
+func (s *apiServer) listOffers(w http.ResponseWriter, r *http.Request) {
+	page, err := s.filterOffers(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *apiServer) filterOffers(r *http.Request) (offerPage, error) {
+	ctx := r.Context()
+	limit, err := queryInt(r, "limit", defaultPageSize, 1, maxPageSize)
+	if err != nil {
+		return offerPage{}, err
+	}
+	offset, err := queryInt(r, "offset", 0, 0, 1<<62)
+	if err != nil {
+		return offerPage{}, err
+	}
+	minSalary, err := queryFloat(r, "min_salary")
+	if err != nil {
+		return offerPage{}, err
+	}
+	maxSalary, err := queryFloat(r, "max_salary")
+	if err != nil {
+		return offerPage{}, err
+	}
+	includeClosed, err := queryBool(r, "include_closed")
+	if err != nil {
+		return offerPage{}, err
+	}
+	group, err := queryBool(r, "group")
+	if err != nil {
+		return offerPage{}, err
+	}
+
+	skill := queryString(r, "skill")
+	if skill.Valid {
+		skill.String, skill.Valid = s.norm.Canonical(skill.String)
+	}
+	filter := database.CountFilteredJobOffersParams{
+		Source:        queryString(r, "source"),
+		Company:       queryString(r, "company"),
+		WorkMode:      queryString(r, "work_mode"),
+		City:          queryString(r, "city"),
+		Skill:         skill,
+		MinMonthly:    minSalary,
+		MaxMonthly:    maxSalary,
+		Currency:      queryString(r, "currency"),
+		IncludeClosed: includeClosed,
+		OnePerGroup:   group,
+	}
+
+	total, err := s.q.CountFilteredJobOffers(ctx, filter)
+	if err != nil {
+		return offerPage{}, err
+	}
+	rows, err := s.q.FilterJobOffers(ctx, database.FilterJobOffersParams{
+		Source:        filter.Source,
+		Company:       filter.Company,
+		WorkMode:      filter.WorkMode,
+		City:          filter.City,
+		Skill:         filter.Skill,
+		MinMonthly:    filter.MinMonthly,
+		MaxMonthly:    filter.MaxMonthly,
+		Currency:      filter.Currency,
+		IncludeClosed: filter.IncludeClosed,
+		OnePerGroup:   filter.OnePerGroup,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return offerPage{}, err
+	}
+
+	page := offerPage{Offers: make([]apiOffer, 0, len(rows)), Total: total, Limit: limit, Offset: offset}
+	for _, row := range rows {
+		o, err := s.offer(ctx, row)
+		if err != nil {
+			return offerPage{}, err
+		}
+		page.Offers = append(page.Offers, o)
+	}
+	return page, nil
+}
+
+// GET /api/offers/{id}
+func (s *apiServer) getOffer(w http.ResponseWriter, r *http.Request) {
+	row, err := s.q.GetJobOffer(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	o, err := s.offer(r.Context(), row)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+// GET /api/offers/{id}/history, versions oldest first
+func (s *apiServer) offerHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := s.q.GetJobOffer(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rows, err := s.q.ListJobOfferVersions(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, groupVersions(rows))
+}
+
+// GET /api/stats/sources
+func (s *apiServer) sourceStats(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.q.CountJobOffersBySource(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// GET /api/stats/skills?limit=
+func (s *apiServer) skillStats(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultTopSize, 1, maxPageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rows, err := s.q.CountJobOffersBySkill(r.Context(), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// GET /api/stats/cities?limit=
+func (s *apiServer) cityStats(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultTopSize, 1, maxPageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rows, err := s.q.CountJobOffersByCity(r.Context(), limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// GET /api/stats/salaries, monthly amounts per contract type and currency
+func (s *apiServer) salaryStats(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.q.SalaryStatsByContract(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	stats := make([]salaryStats, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, salaryStats{
+			Contract:      row.ContractType,
+			Currency:      row.Currency.String,
+			Offers:        row.Offers,
+			AvgMonthlyMin: row.AvgMonthlyMin,
+			AvgMonthlyMax: row.AvgMonthlyMax,
+			MinMonthly:    row.MinMonthly,
+			MaxMonthly:    row.MaxMonthly,
+		})
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,134 @@
+package iternal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	db := newTestDB(t)
+	ctx := context.Background()
+	jobs := []scraper.JobOffer{
+		{
+			Title:            "Senior Go Developer",
+			Company:          "Acme Software",
+			URL:              "https://www.pracuj.pl/praca/go,oferta,1",
+			Source:           "pracuj.pl",
+			Skills:           []string{"Go", "Kubernetes"},
+			SalaryEmployment: "18 000–24 000 zł brutto / mies.",
+			Place:            scraper.Location{Cities: []string{"Warszawa"}, Country: "PL", WorkMode: scraper.WorkModeHybrid},
+		},
+		{
+			Title:     "Java Developer",
+			Company:   "Beta",
+			URL:       "https://justjoin.it/job-offer/java",
+			Source:    "justjoin.it",
+			Skills:    []string{"Java", "Kubernetes"},
+			SalaryB2B: "10 000 - 12 000 PLN net/month",
+			Place:     scraper.Location{WorkMode: scraper.WorkModeRemote},
+		},
+	}
+	for _, job := range jobs {
+		require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	}
+	srv := httptest.NewServer(NewServer(db, skills.New(nil)))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getJSON(t *testing.T, url string, wantStatus int, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, wantStatus, resp.StatusCode, url)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func TestServerListOffers(t *testing.T) {
+	srv := newTestServer(t)
+
+	var page offerPage
+	getJSON(t, srv.URL+"/api/offers", http.StatusOK, &page)
+	assert.EqualValues(t, 2, page.Total)
+	assert.Len(t, page.Offers, 2)
+
+	filters := map[string]string{
+		"?source=justjoin.it":            "Java Developer",
+		"?company=acme":                  "Senior Go Developer",
+		"?skill=golang":                  "Senior Go Developer",
+		"?city=Warszawa":                 "Senior Go Developer",
+		"?work_mode=remote":              "Java Developer",
+		"?min_salary=20000&currency=PLN": "Senior Go Developer",
+		"?max_salary=11000":              "Java Developer",
+		// newest first
+		"?skill=kubernetes&limit=1&offset=1": "Senior Go Developer",
+	}
+	for query, title := range filters {
+		page = offerPage{}
+		getJSON(t, srv.URL+"/api/offers"+query, http.StatusOK, &page)
+		require.Len(t, page.Offers, 1, query)
+		assert.Equal(t, title, page.Offers[0].Title, query)
+	}
+
+	var apiErr map[string]string
+	getJSON(t, srv.URL+"/api/offers?limit=100000", http.StatusBadRequest, &apiErr)
+	assert.Contains(t, apiErr["error"], "limit")
+}
+
+func TestServerGetOffer(t *testing.T) {
+	srv := newTestServer(t)
+
+	var page offerPage
+	getJSON(t, srv.URL+"/api/offers?source=pracuj.pl", http.StatusOK, &page)
+	require.Len(t, page.Offers, 1)
+
+	var offer apiOffer
+	getJSON(t, srv.URL+"/api/offers/"+page.Offers[0].ID, http.StatusOK, &offer)
+	assert.Equal(t, []string{"Warszawa"}, offer.Place.Cities)
+	require.Len(t, offer.Salaries, 1)
+	assert.Equal(t, 24000.0, offer.Salaries[0].MonthlyMax)
+	require.Len(t, offer.Postings, 1, "not grouped yet, the offer is its own job")
+	assert.Equal(t, offer.URL, offer.Postings[0].URL)
+
+	var history []OfferVersion
+	getJSON(t, srv.URL+"/api/offers/"+page.Offers[0].ID+"/history", http.StatusOK, &history)
+	assert.Empty(t, history)
+
+	var apiErr map[string]string
+	getJSON(t, srv.URL+"/api/offers/nope", http.StatusNotFound, &apiErr)
+	getJSON(t, srv.URL+"/api/offers/nope/history", http.StatusNotFound, &apiErr)
+}
+
+func TestServerStats(t *testing.T) {
+	srv := newTestServer(t)
+
+	var skillStats []struct {
+		Skill  string `json:"skill"`
+		Offers int64  `json:"offers"`
+	}
+	getJSON(t, srv.URL+"/api/stats/skills?limit=1", http.StatusOK, &skillStats)
+	require.Len(t, skillStats, 1)
+	assert.Equal(t, "Kubernetes", skillStats[0].Skill)
+	assert.EqualValues(t, 2, skillStats[0].Offers)
+
+	var sources []map[string]any
+	getJSON(t, srv.URL+"/api/stats/sources", http.StatusOK, &sources)
+	assert.Len(t, sources, 2)
+
+	var salaries []salaryStats
+	getJSON(t, srv.URL+"/api/stats/salaries", http.StatusOK, &salaries)
+	require.Len(t, salaries, 2)
+	assert.Equal(t, "b2b", salaries[0].Contract)
+	assert.Equal(t, "PLN", salaries[0].Currency)
+}
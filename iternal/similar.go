@@ -0,0 +1,145 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/embedding"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+)
+
+const embedBatchSize = 32
+
+// SimilarResult is an offer with its cosine similarity to the searched text or offer
+type SimilarResult struct {
+	Offer scraper.JobOffer `json:"offer"`
+	Score float64          `json:"score"`
+}
+
+// text that gets embedded, title and skills first since some embedders truncate long input
+func embeddingText(job scraper.JobOffer) string {
+	parts := []string{job.Title, job.Company, strings.Join(job.Skills, ", "), htmlToText(job.Description)}
+	return strings.Join(parts, "\n")
+}
+
+// SimilarToText embeds text and returns the closest stored offers embedded with the same model
+func SimilarToText(ctx context.Context, db *sql.DB, emb embedding.Embedder, text string, limit int) ([]SimilarResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("empty text")
+	}
+	vectors, err := emb.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return rankSimilar(ctx, database.New(db), emb.Model(), vectors[0], "", limit)
+}
+
+// SimilarToOffer returns offers closest to a stored one, the offer itself is left out.
+// Its stored vector is used when it matches the model, otherwise it is embedded now.
+func SimilarToOffer(ctx context.Context, db *sql.DB, emb embedding.Embedder, id string, limit int) ([]SimilarResult, error) {
+	q := database.New(db)
+	row, err := q.GetJobOffer(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no offer with id %q", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vector []float32
+	if row.EmbeddingModel.String == emb.Model() && row.Embedding != nil {
+		if vector, err = embedding.Decode(row.Embedding); err != nil {
+			return nil, err
+		}
+	} else {
+		vectors, err := emb.Embed(ctx, []string{embeddingText(jobOfferFromRow(row))})
+		if err != nil {
+			return nil, err
+		}
+		vector = vectors[0]
+	}
+	return rankSimilar(ctx, q, emb.Model(), vector, id, limit)
+}
+
+// brute force over every stored vector, fine for the tens of thousands of offers we keep
+func rankSimilar(ctx context.Context, q *database.Queries, model string, vector []float32, excludeID string, limit int) ([]SimilarResult, error) {
+	rows, err := q.ListJobOfferEmbeddings(ctx, sql.NullString{String: model, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	var candidates []scored
+	for _, row := range rows {
+		if row.ID == excludeID {
+			continue
+		}
+		v, err := embedding.Decode(row.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("offer %s: %w", row.ID, err)
+		}
+		candidates = append(candidates, scored{row.ID, embedding.Cosine(vector, v)})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	results := make([]SimilarResult, 0, len(candidates))
+	for _, c := range candidates {
+		row, err := q.GetJobOffer(ctx, c.id)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SimilarResult{Offer: jobOfferFromRow(row), Score: c.score})
+	}
+	return results, nil
+}
+
+// EmbedMissing embeds every offer without a vector from emb, e.g. after switching models.
+// Returns how many offers were embedded.
+func EmbedMissing(ctx context.Context, db *sql.DB, emb embedding.Embedder) (int, error) {
+	q := database.New(db)
+	model := sql.NullString{String: emb.Model(), Valid: true}
+	done := 0
+	for {
+		rows, err := q.ListJobOffersWithoutEmbedding(ctx, database.ListJobOffersWithoutEmbeddingParams{
+			EmbeddingModel: model,
+			Limit:          embedBatchSize,
+		})
+		if err != nil {
+			return done, err
+		}
+		if len(rows) == 0 {
+			return done, nil
+		}
+
+		texts := make([]string, len(rows))
+		for i, row := range rows {
+			texts[i] = embeddingText(jobOfferFromRow(row))
+		}
+		vectors, err := emb.Embed(ctx, texts)
+		if err != nil {
+			return done, err
+		}
+		for i, row := range rows {
+			params := database.UpdateJobOfferEmbeddingParams{
+				Embedding:      embedding.Encode(vectors[i]),
+				EmbeddingModel: model,
+				ID:             row.ID,
+			}
+			if err := q.UpdateJobOfferEmbedding(ctx, params); err != nil {
+				return done, err
+			}
+		}
+		done += len(rows)
+	}
+}
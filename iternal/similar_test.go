@@ -0,0 +1,58 @@
+package iternal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/embedding"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarOffers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	emb := embedding.NewHash(256)
+
+	jobs := []scraper.JobOffer{
+		{Title: "Go Developer", URL: "https://a/1", Source: "pracuj.pl", Skills: []string{"Go", "Kubernetes", "PostgreSQL"}},
+		{Title: "Senior Golang Engineer", URL: "https://a/2", Source: "justjoin.it", Skills: []string{"Go", "Kubernetes", "gRPC"}},
+		{Title: "Specjalista ds. księgowości", URL: "https://a/3", Source: "pracuj.pl", Description: "<p>Excel, SAP</p>"},
+	}
+	for _, job := range jobs {
+		require.NoError(t, saveJobOffer(ctx, db, job, emb))
+	}
+
+	results, err := SimilarToText(ctx, db, emb, "go kubernetes", 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.NotEqual(t, "https://a/3", results[0].Offer.URL)
+	assert.NotEqual(t, "https://a/3", results[1].Offer.URL)
+
+	offers, err := database.New(db).ListJobOffersBySource(ctx, database.ListJobOffersBySourceParams{Source: "justjoin.it", Limit: 1})
+	require.NoError(t, err)
+	results, err = SimilarToOffer(ctx, db, emb, offers[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2, "the offer itself is excluded")
+	assert.Equal(t, "https://a/1", results[0].Offer.URL)
+	assert.Greater(t, results[0].Score, results[1].Score)
+}
+
+func TestEmbedMissing(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, saveJobOffer(ctx, db, scraper.JobOffer{Title: "Go Developer", URL: "https://a/1", Source: "pracuj.pl"}, nil))
+	require.NoError(t, saveJobOffer(ctx, db, scraper.JobOffer{Title: "Java Developer", URL: "https://a/2", Source: "pracuj.pl"}, embedding.NewHash(64)))
+
+	// switching models re-embeds everything
+	n, err := EmbedMissing(ctx, db, embedding.NewHash(128))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = EmbedMissing(ctx, db, embedding.NewHash(128))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
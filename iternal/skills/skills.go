@@ -0,0 +1,124 @@
+package skills
+
+import (
+	"strings"
+)
+
+// canonical name to lowercase spellings seen on the boards, the canonical name itself always matches.
+// Only spellings that can't mean anything else, "node" or "spring boot" are left to skills.aliases.
+var builtinAliases = map[string][]string{
+	"Go":               {"golang", "go lang"},
+	"JavaScript":       {"js", "java script", "ecmascript", "es6"},
+	"TypeScript":       {"ts"},
+	"Node.js":          {"nodejs", "node js"},
+	"React":            {"react.js", "reactjs", "react js"},
+	"Angular":          {"angularjs", "angular.js", "angular 2+"},
+	"Vue.js":           {"vue", "vuejs", "vue js"},
+	"Next.js":          {"nextjs"},
+	"Python":           {"python3", "python 3"},
+	"C#":               {"c sharp", "csharp"},
+	"C++":              {"cpp", "c plus plus"},
+	".NET":             {"dotnet", "dot net", ".net core", "asp.net", "asp.net core"},
+	"Java":             {"java se", "java ee"},
+	"Spring":           {"spring framework"},
+	"Kotlin":           {},
+	"PHP":              {},
+	"Ruby on Rails":    {"rails", "ror"},
+	"Kubernetes":       {"k8s"},
+	"Docker":           {"docker compose", "docker-compose"},
+	"Terraform":        {},
+	"AWS":              {"amazon web services"},
+	"GCP":              {"google cloud", "google cloud platform"},
+	"Azure":            {"microsoft azure", "ms azure"},
+	"PostgreSQL":       {"postgres", "postgre", "postgresql db", "psql"},
+	"MySQL":            {"my sql"},
+	"MS SQL":           {"mssql", "sql server", "microsoft sql server", "t-sql", "tsql"},
+	"MongoDB":          {"mongo"},
+	"Elasticsearch":    {"elastic search"},
+	"Redis":            {},
+	"Kafka":            {"apache kafka"},
+	"RabbitMQ":         {"rabbit mq"},
+	"GraphQL":          {"graph ql"},
+	"REST API":         {"restful", "restful api", "rest apis"},
+	"CI/CD":            {"ci / cd", "ci-cd", "cicd"},
+	"Git":              {},
+	"Linux":            {"unix/linux", "linux/unix"},
+	"SQL":              {},
+	"HTML":             {"html5"},
+	"CSS":              {"css3"},
+	"Machine Learning": {"ml"},
+	"English":          {"angielski", "język angielski", "jezyk angielski"},
+	"Polish":           {"polski", "język polski", "jezyk polski"},
+}
+
+// section headings nofluff mixes into the requirements list
+var noise = map[string]bool{
+	"obowiązkowe":   true,
+	"mile widziane": true,
+	"wymagania":     true,
+	"must have":     true,
+	"nice to have":  true,
+	"requirements":  true,
+}
+
+// Normalizer maps raw skill names from different boards to one canonical spelling
+type Normalizer struct {
+	aliases map[string]string
+}
+
+// New returns a normalizer with the builtin dictionary extended by extra,
+// extra maps canonical names to their aliases and wins over builtin entries
+func New(extra map[string][]string) *Normalizer {
+	n := &Normalizer{aliases: make(map[string]string)}
+	n.add(builtinAliases)
+	n.add(extra)
+	return n
+}
+
+func (n *Normalizer) add(aliases map[string][]string) {
+	for canonical, spellings := range aliases {
+		n.aliases[key(canonical)] = canonical
+		for _, s := range spellings {
+			n.aliases[key(s)] = canonical
+		}
+	}
+}
+
+// collapses whitespace and trims list punctuation
+func clean(name string) string {
+	name = strings.ReplaceAll(name, "\u00a0", " ")
+	name = strings.Trim(name, " \t\r\n•·-–*,;:")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func key(name string) string {
+	return strings.ToLower(clean(name))
+}
+
+// Canonical returns the canonical spelling of one skill, unknown skills are only trimmed.
+// ok is false for empty values and section headings.
+func (n *Normalizer) Canonical(raw string) (string, bool) {
+	k := key(raw)
+	if k == "" || noise[k] {
+		return "", false
+	}
+	if canonical, found := n.aliases[k]; found {
+		return canonical, true
+	}
+	return clean(raw), true
+}
+
+// Normalize canonicalizes skills keeping the first occurrence order and dropping duplicates
+func (n *Normalizer) Normalize(raw []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, r := range raw {
+		canonical, ok := n.Canonical(r)
+		if !ok || seen[key(canonical)] {
+			continue
+		}
+		seen[key(canonical)] = true
+		result = append(result, canonical)
+	}
+	return result
+}
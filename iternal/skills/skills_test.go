@@ -0,0 +1,29 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	n := New(nil)
+
+	got := n.Normalize([]string{"Golang", " go ", "JS", "k8s", "Kubernetes", "Mile widziane", "", "PostgreSQL", "postgres", "Some Internal  Tool"})
+	assert.Equal(t, []string{"Go", "JavaScript", "Kubernetes", "PostgreSQL", "Some Internal Tool"}, got)
+
+	// ambiguous spellings and distinct skills stay as the board wrote them
+	got = n.Normalize([]string{"Spring Boot", "Spring", "node", "TF", "REST", "Elastic"})
+	assert.Equal(t, []string{"Spring Boot", "Spring", "node", "TF", "REST", "Elastic"}, got)
+}
+
+func TestNormalizeUserAliases(t *testing.T) {
+	n := New(map[string][]string{
+		"Golang":  {"go"},
+		"Airflow": {"apache airflow"},
+	})
+
+	assert.Equal(t, []string{"Golang", "Airflow"}, n.Normalize([]string{"Go", "Apache Airflow", "airflow"}))
+	// builtin aliases still apply
+	assert.Equal(t, []string{"JavaScript"}, n.Normalize([]string{"js"}))
+}
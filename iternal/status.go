@@ -0,0 +1,61 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pfczx/jobscraper/database"
+)
+
+// SyncListedOffers compares a fresh listing of a source against the database,
+// closed offers listed again are reopened, open offers missing from the listing
+// get closed_at set. Offers listed but not scraped yet are left alone, the next
+// scrape inserts them.
+func SyncListedOffers(ctx context.Context, db *sql.DB, source string, urls []string) (closed, reopened int, err error) {
+	// an empty listing is a failed collect, not a board without offers
+	if len(urls) == 0 {
+		return 0, 0, errors.New("empty listing, not closing anything")
+	}
+	listed := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		listed[urlNormalizer(url)] = true
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+	querier := database.New(db).WithTx(tx)
+
+	states, err := querier.ListJobOfferStatesBySource(ctx, source)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, state := range states {
+		switch {
+		case listed[state.Url]:
+			if !state.ClosedAt.Valid {
+				continue
+			}
+			if err := querier.ReopenJobOffer(ctx, state.ID); err != nil {
+				return 0, 0, err
+			}
+			reopened++
+		case !state.ClosedAt.Valid:
+			if err := querier.CloseJobOffer(ctx, state.ID); err != nil {
+				return 0, 0, err
+			}
+			closed++
+		}
+	}
+	return closed, reopened, tx.Commit()
+}
+
+// closes the stored offer of a page the scraper found expired,
+// false when there was no open offer with that url
+func closeExpiredOffer(ctx context.Context, db *sql.DB, url string) (bool, error) {
+	n, err := database.New(db).CloseJobOfferByUrl(ctx, urlNormalizer(url))
+	return n > 0, err
+}
@@ -0,0 +1,81 @@
+package iternal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pfczx/jobscraper/database"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/skills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncListedOffers(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	for _, url := range []string{"https://justjoin.it/job-offer/a", "https://justjoin.it/job-offer/b"} {
+		require.NoError(t, saveJobOffer(ctx, db, scraper.JobOffer{Title: "Go", URL: url, Source: "justjoin.it"}, nil))
+	}
+	other := scraper.JobOffer{Title: "Go", URL: "https://nofluffjobs.com/pl/job/c", Source: "nofluffjobs.com"}
+	require.NoError(t, saveJobOffer(ctx, db, other, nil))
+
+	_, _, err := SyncListedOffers(ctx, db, "justjoin.it", nil)
+	assert.Error(t, err, "empty listing should not close everything")
+
+	// tracking params are dropped the same way the collector drops them
+	closed, reopened, err := SyncListedOffers(ctx, db, "justjoin.it", []string{"https://justjoin.it/job-offer/a?utm_source=x", "https://justjoin.it/job-offer/new"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	assert.Equal(t, 0, reopened)
+
+	active, err := database.New(db).ListJobOffers(ctx, database.ListJobOffersParams{Limit: 10})
+	require.NoError(t, err)
+	var urls []string
+	for _, o := range active {
+		urls = append(urls, o.Url)
+	}
+	assert.ElementsMatch(t, []string{"https://justjoin.it/job-offer/a", "https://nofluffjobs.com/pl/job/c"}, urls)
+
+	closed, reopened, err = SyncListedOffers(ctx, db, "justjoin.it", []string{"https://justjoin.it/job-offer/a", "https://justjoin.it/job-offer/b"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, closed)
+	assert.Equal(t, 1, reopened)
+}
+
+func TestExpiredOfferIsClosed(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	job := scraper.JobOffer{Title: "Go Developer", URL: "https://www.pracuj.pl/praca/go,oferta,1", Source: "pracuj.pl"}
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+
+	ok, err := closeExpiredOffer(ctx, db, job.URL+"?s=1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = closeExpiredOffer(ctx, db, job.URL)
+	require.NoError(t, err)
+	assert.False(t, ok, "already closed")
+
+	srv := httptest.NewServer(NewServer(db, skills.New(nil)))
+	defer srv.Close()
+	var page offerPage
+	getJSON(t, srv.URL+"/api/offers", http.StatusOK, &page)
+	assert.EqualValues(t, 0, page.Total)
+
+	page = offerPage{}
+	getJSON(t, srv.URL+"/api/offers?include_closed=true", http.StatusOK, &page)
+	require.Len(t, page.Offers, 1)
+	assert.NotNil(t, page.Offers[0].ClosedAt)
+
+	// a rescrape means the offer is back
+	require.NoError(t, saveJobOffer(ctx, db, job, nil))
+	page = offerPage{}
+	getJSON(t, srv.URL+"/api/offers", http.StatusOK, &page)
+	require.Len(t, page.Offers, 1)
+	assert.Nil(t, page.Offers[0].ClosedAt)
+
+	var apiErr map[string]string
+	getJSON(t, srv.URL+"/api/offers?include_closed=maybe", http.StatusBadRequest, &apiErr)
+}
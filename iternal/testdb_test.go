@@ -0,0 +1,28 @@
+package iternal
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pfczx/jobscraper/iternal/migrate"
+	"github.com/pfczx/jobscraper/sql/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// opens a fresh database migrated like the binary does it, the fts5 migration only
+// when the driver was built with it
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "jobs.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	set, err := migrate.Load(schema.FS)
+	require.NoError(t, err)
+	_, err = set.Up(context.Background(), db)
+	require.NoError(t, err)
+	return db
+}
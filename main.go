@@ -1,97 +1,106 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strings"
-	"sync"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/pfczx/jobscraper/iternal"
-	"github.com/pfczx/jobscraper/iternal/scraper"
-	"github.com/pfczx/jobscraper/iternal/scraper/scrapers"
-
-	//"github.com/pyrczuu/urlScraper"
-	//"github.com/pyrczuu/nofluff_scraper"
-	"github.com/pfczx/jobscraper/urlgoscraper"
 )
 
-func main() {
-	reader := bufio.NewReader(os.Stdin)
-	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-	db, err := sql.Open("sqlite3", "./database/jobs.db")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer db.Close()
-	var wg sync.WaitGroup
-
-	ctx := context.Background()
-
-	for {
-		fmt.Println("1 - urlscraping")
-		fmt.Println("2 - job scraping from url list")
-		fmt.Println("3 - exit")
-
-		choice, _ := reader.ReadString('\n')
-		choice = strings.TrimSpace(choice)
+// exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
 
-		switch choice {
-		case "1":
-			wg.Add(3)
-			go func() {
-				defer wg.Done()
-				pracujUrls := urlsgocraper.CollectPracujPl(ctx)
-				urlsgocraper.SaveUrls("pracujUrls.txt", pracujUrls)
-			}()
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
 
-			go func() {
-				defer wg.Done()
-				noflufUrls, _ := urlsgocraper.NofluffScrollAndRead(ctx)
-				urlsgocraper.SaveUrls("noflufUrls.txt", noflufUrls)
-			}()
+var commands = []command{
+	{name: "collect-urls", summary: "collect offer urls from job boards into url files", run: runCollectUrls},
+	{name: "scrape", summary: "scrape offers from url files into the database", run: runScrape},
+	{name: "retry-failed", summary: "scrape urls that failed before again, with backoff", run: runRetryFailed},
+	{name: "export", summary: "export stored offers as json or csv", run: runExport},
+	{name: "search", summary: "full-text search over stored offers", run: runSearch},
+	{name: "dedupe", summary: "group offers of the same job posted on several boards", run: runDedupe},
+	{name: "embed", summary: "embed stored offers that have no vector from the configured embedder", run: runEmbed},
+	{name: "similar", summary: "find offers similar to a text or a stored offer", run: runSimilar},
+	{name: "company", summary: "show a company with its open offers, salaries and top skills", run: runCompany},
+	{name: "history", summary: "show the recorded changes of an offer", run: runHistory},
+	{name: "runs", summary: "list recent scrape runs with per-source stats", run: runRuns},
+	{name: "migrate", summary: "apply, revert or list schema migrations, other commands migrate up on start", run: runMigrate},
+	{name: "serve", summary: "serve stored offers over a read-only json api", run: runServe},
+}
 
-			go func() {
-				defer wg.Done()
-				justjoinUrls, _ := urlsgocraper.JustJoinScrollAndRead(ctx)
-				urlsgocraper.SaveUrls("justjoinUrls.txt", justjoinUrls)
-			}()
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jobscraper <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "run 'jobscraper <command> -h' for command flags")
+}
 
-			wg.Wait()
+func main() {
+	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 
-		case "2":
-			pracujUrls, _ := urlsgocraper.LoadUrls("pracujUrls.txt")
-			noflufUrls, _ := urlsgocraper.LoadUrls("noflufUrls.txt")
-			justjoinUrls, _ := urlsgocraper.LoadUrls("justjoinUrls.txt")
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
 
-			scrapersList := []scraper.Scraper{
-				scrapers.NewNoFluffScraper(noflufUrls),
-				scrapers.NewPracujScraper(pracujUrls),
-				scrapers.NewJustJoinItScraper(justjoinUrls),
-			}
-			parralel := false
-			fmt.Println("Type y/yes if u want parralel scraping")
-			choiceParralel, _ := reader.ReadString('\n')
-			choiceParralel = strings.TrimSpace(choiceParralel)
-			if choiceParralel == "y" || choiceParralel =="yes"{
-				parralel = true
-			}
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				iternal.StartCollector(ctx, db, scrapersList, parralel)
-			}()
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		os.Exit(exitOK)
+	}
 
-			wg.Wait()
-			log.Println("Scraping Completed")
-		case "3":
-			os.Exit(0)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+			break
 		}
-
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(exitUsage)
 	}
 
+	//ctrl+c cancels the running command instead of killing the browser mid write
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := cmd.run(ctx, os.Args[2:])
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, flag.ErrHelp):
+		stop()
+		os.Exit(exitOK)
+	case errors.Is(err, errUsage):
+		// bare errUsage means flag already printed the problem
+		if err != errUsage {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		}
+		stop()
+		os.Exit(exitUsage)
+	default:
+		log.Printf("%s: %v", cmd.name, err)
+		stop()
+		os.Exit(exitFailure)
+	}
 }
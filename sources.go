@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/pfczx/jobscraper/iternal/scraper"
+	"github.com/pfczx/jobscraper/iternal/scraper/scrapers"
+	"github.com/pfczx/jobscraper/urlgoscraper"
+)
+
+var errUsage = errors.New("invalid usage")
+
+// job board wiring shared by the commands, name is the --source value
+type source struct {
+	name string
+	// value stored in job_offers.source
+	sourceName string
+	urlFile    string
+	settings   func(cfg *config.Config) config.Source
+	// progress goes to cp so an interrupted collect can resume, captchaMarkers come from the site,
+	// pool may be nil.
+	// nil for boards added in sites_dir, their urls come from url files
+	collect func(ctx context.Context, browser config.Browser, cfg config.Source, captchaMarkers []string, pool *fetcher.Pool, cp urlsgocraper.Checkpoint) ([]string, error)
+	// selectors of the offer pages
+	site scrapers.Site
+}
+
+func (s source) newScraper(f fetcher.Fetcher, cfg config.Source, urls []string, captcha scraper.CaptchaStrategy, stats *scraper.Stats) scraper.Scraper {
+	return scrapers.NewSiteScraper(s.site, f, cfg, urls, captcha, stats)
+}
+
+// the boards with a url collector, their sites are the builtin definitions
+var builtinSources = []source{
+	{
+		name:       "pracuj",
+		sourceName: "pracuj.pl",
+		urlFile:    "pracujUrls.txt",
+		settings:   func(cfg *config.Config) config.Source { return cfg.Sources.Pracuj },
+		collect: func(ctx context.Context, browser config.Browser, cfg config.Source, captchaMarkers []string, pool *fetcher.Pool, cp urlsgocraper.Checkpoint) ([]string, error) {
+			f := fetcher.New(ctx, browser, cfg, cfg.CollectRateLimit(), captchaMarkers, pool)
+			defer f.Close()
+			urls, err := urlsgocraper.CollectPracujPl(ctx, f, cfg, cp)
+			if err != nil {
+				return nil, err
+			}
+			if len(urls) == 0 {
+				return nil, errors.New("no urls collected")
+			}
+			return urls, nil
+		},
+	},
+	{
+		name:       "nofluff",
+		sourceName: "nofluffjobs.com",
+		urlFile:    "noflufUrls.txt",
+		settings:   func(cfg *config.Config) config.Source { return cfg.Sources.Nofluff },
+		collect: func(ctx context.Context, browser config.Browser, cfg config.Source, captchaMarkers []string, pool *fetcher.Pool, cp urlsgocraper.Checkpoint) ([]string, error) {
+			// listing needs scrolling so it always runs in chrome
+			b := fetcher.NewChrome(ctx, browser, cfg.DataDir, pool)
+			defer b.Close()
+			return urlsgocraper.NofluffScrollAndRead(ctx, b, cfg, cp)
+		},
+	},
+	{
+		name:       "justjoin",
+		sourceName: "justjoin.it",
+		urlFile:    "justjoinUrls.txt",
+		settings:   func(cfg *config.Config) config.Source { return cfg.Sources.Justjoin },
+		collect: func(ctx context.Context, browser config.Browser, cfg config.Source, captchaMarkers []string, pool *fetcher.Pool, cp urlsgocraper.Checkpoint) ([]string, error) {
+			// listing needs scrolling so it always runs in chrome
+			b := fetcher.NewChrome(ctx, browser, cfg.DataDir, pool)
+			defer b.Close()
+			return urlsgocraper.JustJoinScrollAndRead(ctx, b, cfg, cp)
+		},
+	},
+}
+
+// loadSources pairs the builtin boards with their site definitions. A definition in
+// sites_dir replaces the builtin one of the same name or adds a board scraped from url files.
+func loadSources(cfg *config.Config) ([]source, error) {
+	var custom []scrapers.Site
+	if cfg.SitesDir != "" {
+		var err error
+		if custom, err = scrapers.LoadSites(cfg.SitesDir); err != nil {
+			return nil, fmt.Errorf("sites_dir: %w", err)
+		}
+	}
+
+	var all []source
+	for _, s := range builtinSources {
+		s.site, _ = scrapers.BuiltinSite(s.name)
+		if i := slices.IndexFunc(custom, func(site scrapers.Site) bool { return site.Name == s.name }); i >= 0 {
+			// stored offers are matched by source, a fixed definition must keep it
+			if custom[i].Source != s.sourceName {
+				return nil, fmt.Errorf("site %s: source must stay %q, got %q", s.name, s.sourceName, custom[i].Source)
+			}
+			s.site = custom[i]
+		}
+		all = append(all, s)
+	}
+	for _, site := range custom {
+		if slices.ContainsFunc(builtinSources, func(s source) bool { return s.name == site.Name }) {
+			continue
+		}
+		for _, s := range all {
+			if s.sourceName == site.Source {
+				return nil, fmt.Errorf("sites %s and %s share source %q", s.name, site.Name, site.Source)
+			}
+		}
+		all = append(all, source{
+			name:       site.Name,
+			sourceName: site.Source,
+			urlFile:    site.Name + "Urls.txt",
+			settings:   func(cfg *config.Config) config.Source { return cfg.Sources.Source(site.Name) },
+			site:       site,
+		})
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Sources.Other)) {
+		if !slices.ContainsFunc(all, func(s source) bool { return s.name == name }) {
+			return nil, fmt.Errorf("sources.%s: no site definition named %q in sites_dir", name, name)
+		}
+	}
+	return all, nil
+}
+
+func sourceNames(sources []source) []string {
+	names := make([]string, 0, len(sources))
+	for _, s := range sources {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// parses comma separated --source value, empty or "all" selects every source
+func parseSources(cfg *config.Config, value string) ([]source, error) {
+	sources, err := loadSources(cfg)
+	if err != nil {
+		return nil, err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" || value == "all" {
+		return sources, nil
+	}
+
+	var selected []source
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		found := false
+		for _, s := range sources {
+			if s.name == name {
+				selected = append(selected, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: unknown source %q (available: %s)", errUsage, name, strings.Join(sourceNames(sources), ","))
+		}
+		seen[name] = true
+	}
+	return selected, nil
+}
+
+// flag.ContinueOnError prints the problem itself, we only map it to an exit code
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", errUsage, fs.Args())
+	}
+	return nil
+}
+
+// loads config (defaults < file < env) and then applies only the flags that were set on the command line
+func loadConfig(fs *flag.FlagSet, path string, flagOverrides map[string]func(cfg *config.Config)) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if apply, ok := flagOverrides[f.Name]; ok {
+			apply(cfg)
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return cfg, nil
+}
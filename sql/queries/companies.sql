@@ -0,0 +1,68 @@
+-- name: UpsertCompany :one
+-- the first spelling seen stays the display name
+INSERT INTO companies (name, normalized_name) VALUES (?, ?)
+ON CONFLICT(normalized_name) DO UPDATE SET normalized_name = excluded.normalized_name
+RETURNING *;
+
+-- name: GetCompanyByNormalizedName :one
+SELECT * FROM companies
+WHERE normalized_name = ?;
+
+-- name: GetCompanyByAlias :one
+SELECT companies.* FROM companies
+JOIN company_aliases ON company_aliases.company_id = companies.id
+WHERE company_aliases.alias = ?;
+
+-- name: FindCompanies :many
+SELECT * FROM companies
+WHERE instr(normalized_name, sqlc.arg(normalized_name)) > 0
+ORDER BY normalized_name
+LIMIT sqlc.arg(limit);
+
+-- name: InsertCompanyAlias :exec
+INSERT OR IGNORE INTO company_aliases (alias, company_id) VALUES (?, ?);
+
+-- name: ListCompanyAliases :many
+SELECT alias FROM company_aliases
+WHERE company_id = ?
+ORDER BY alias;
+
+-- name: UpsertCompanyProfile :exec
+INSERT INTO company_profiles (company_id, source, url) VALUES (?, ?, ?)
+ON CONFLICT(company_id, source) DO UPDATE SET url = excluded.url;
+
+-- name: ListCompanyProfiles :many
+SELECT source, url FROM company_profiles
+WHERE company_id = ?
+ORDER BY source;
+
+-- name: SetJobOfferCompany :exec
+UPDATE job_offers SET company_id = ? WHERE id = ?;
+
+-- name: ListJobOffersWithoutCompany :many
+-- offers stored before companies existed
+SELECT id, company, source FROM job_offers
+WHERE company_id IS NULL AND company IS NOT NULL AND company != ''
+ORDER BY created_at, rowid;
+
+-- name: CountCompanyOffers :one
+SELECT COUNT(*) AS open_offers, COUNT(DISTINCT COALESCE(posting_group_id, id)) AS jobs
+FROM job_offers
+WHERE company_id = ? AND closed_at IS NULL;
+
+-- name: ListCompanySalaries :many
+-- one offer per job, so a job posted on three boards counts once towards the median
+SELECT job_offer_salaries.contract_type, job_offer_salaries.currency, job_offer_salaries.monthly_min, job_offer_salaries.monthly_max
+FROM job_offer_salaries
+JOIN job_offers ON job_offers.id = job_offer_salaries.job_offer_id
+WHERE job_offers.company_id = ? AND job_offers.closed_at IS NULL
+    AND (job_offers.posting_group_id IS NULL OR job_offers.posting_group_id = job_offers.id)
+ORDER BY job_offer_salaries.contract_type, job_offer_salaries.currency, job_offer_salaries.monthly_min;
+
+-- name: CountCompanySkills :many
+SELECT CAST(json_each.value AS TEXT) AS skill, COUNT(DISTINCT COALESCE(job_offers.posting_group_id, job_offers.id)) AS offers
+FROM job_offers, json_each(job_offers.skills)
+WHERE job_offers.company_id = ? AND job_offers.closed_at IS NULL
+GROUP BY skill
+ORDER BY offers DESC, skill
+LIMIT ?;
@@ -0,0 +1,17 @@
+-- name: ListDiscoveredUrls :many
+SELECT * FROM discovered_urls
+WHERE source = ?
+ORDER BY first_discovered_at, url;
+
+-- name: SetDiscoveredUrlStatus :exec
+UPDATE discovered_urls
+SET status = ?, scraped_at = CURRENT_TIMESTAMP
+WHERE url = ?;
+
+-- name: UpsertDiscoveredUrl :exec
+-- a url listed again after it closed waits for a scrape again
+INSERT INTO discovered_urls (url, source) VALUES (?, ?)
+ON CONFLICT(url) DO UPDATE SET
+    source = excluded.source,
+    last_discovered_at = CURRENT_TIMESTAMP,
+    status = CASE WHEN status = 'closed' THEN 'pending' ELSE status END;
@@ -0,0 +1,14 @@
+-- name: UpdateJobOfferEmbedding :exec
+UPDATE job_offers
+SET embedding = ?, embedding_model = ?
+WHERE id = ?;
+
+-- name: ListJobOfferEmbeddings :many
+SELECT id, embedding FROM job_offers
+WHERE embedding_model = ? AND embedding IS NOT NULL AND closed_at IS NULL;
+
+-- name: ListJobOffersWithoutEmbedding :many
+SELECT * FROM job_offers
+WHERE embedding IS NULL OR embedding_model IS NULL OR embedding_model != ?
+ORDER BY id
+LIMIT ?;
@@ -0,0 +1,20 @@
+-- name: DeleteFailedUrl :exec
+DELETE FROM failed_urls WHERE url = ?;
+
+-- name: GetFailedUrl :one
+SELECT * FROM failed_urls WHERE url = ?;
+
+-- name: ListFailedUrls :many
+SELECT * FROM failed_urls
+WHERE (sqlc.narg(source) IS NULL OR source = sqlc.narg(source))
+ORDER BY permanent, source, last_attempt_at DESC;
+
+-- name: UpsertFailedUrl :exec
+INSERT INTO failed_urls (url, source, error, permanent, attempts, first_failed_at, last_attempt_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(url) DO UPDATE SET
+    source = excluded.source,
+    error = excluded.error,
+    permanent = excluded.permanent,
+    attempts = excluded.attempts,
+    last_attempt_at = excluded.last_attempt_at;
@@ -0,0 +1,64 @@
+-- name: FilterJobOffers :many
+-- every filter is optional, NULL leaves it out, one_per_group keeps one matching offer per job
+WITH matching AS (
+  -- the representative of a job is picked among the offers the filters let through,
+  -- its canonical offer when that one matches, the oldest matching one otherwise
+  SELECT id, ROW_NUMBER() OVER (
+      PARTITION BY COALESCE(posting_group_id, id)
+      ORDER BY posting_group_id = id DESC, created_at, rowid) AS group_rank
+  FROM job_offers
+  WHERE (sqlc.narg(source) IS NULL OR source = sqlc.narg(source))
+    -- any spelling of the company, so every offer of it is found whichever one was typed,
+    -- instr rather than LIKE so % and _ in the input match themselves
+    AND (sqlc.narg(company) IS NULL OR company_id IN (
+        SELECT company_aliases.company_id FROM company_aliases
+        WHERE instr(lower(company_aliases.alias), lower(sqlc.narg(company))) > 0))
+    AND (sqlc.narg(work_mode) IS NULL OR work_mode = sqlc.narg(work_mode))
+    AND (sqlc.narg(city) IS NULL OR EXISTS (
+        SELECT 1 FROM job_offer_cities
+        WHERE job_offer_cities.job_offer_id = job_offers.id AND job_offer_cities.city = sqlc.narg(city)))
+    AND (sqlc.narg(skill) IS NULL OR EXISTS (
+        SELECT 1 FROM json_each(job_offers.skills) WHERE json_each.value = sqlc.narg(skill)))
+    AND ((sqlc.narg(min_monthly) IS NULL AND sqlc.narg(max_monthly) IS NULL AND sqlc.narg(currency) IS NULL) OR EXISTS (
+        SELECT 1 FROM job_offer_salaries
+        WHERE job_offer_salaries.job_offer_id = job_offers.id
+          AND (sqlc.narg(currency) IS NULL OR job_offer_salaries.currency = sqlc.narg(currency))
+          AND (sqlc.narg(min_monthly) IS NULL OR job_offer_salaries.monthly_max >= sqlc.narg(min_monthly))
+          AND (sqlc.narg(max_monthly) IS NULL OR job_offer_salaries.monthly_min <= sqlc.narg(max_monthly))))
+    AND (sqlc.arg(include_closed) OR closed_at IS NULL)
+)
+SELECT * FROM job_offers
+WHERE id IN (SELECT id FROM matching WHERE NOT sqlc.arg(one_per_group) OR group_rank = 1)
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountFilteredJobOffers :one
+WITH matching AS (
+  -- the representative of a job is picked among the offers the filters let through,
+  -- its canonical offer when that one matches, the oldest matching one otherwise
+  SELECT id, ROW_NUMBER() OVER (
+      PARTITION BY COALESCE(posting_group_id, id)
+      ORDER BY posting_group_id = id DESC, created_at, rowid) AS group_rank
+  FROM job_offers
+  WHERE (sqlc.narg(source) IS NULL OR source = sqlc.narg(source))
+    -- any spelling of the company, so every offer of it is found whichever one was typed,
+    -- instr rather than LIKE so % and _ in the input match themselves
+    AND (sqlc.narg(company) IS NULL OR company_id IN (
+        SELECT company_aliases.company_id FROM company_aliases
+        WHERE instr(lower(company_aliases.alias), lower(sqlc.narg(company))) > 0))
+    AND (sqlc.narg(work_mode) IS NULL OR work_mode = sqlc.narg(work_mode))
+    AND (sqlc.narg(city) IS NULL OR EXISTS (
+        SELECT 1 FROM job_offer_cities
+        WHERE job_offer_cities.job_offer_id = job_offers.id AND job_offer_cities.city = sqlc.narg(city)))
+    AND (sqlc.narg(skill) IS NULL OR EXISTS (
+        SELECT 1 FROM json_each(job_offers.skills) WHERE json_each.value = sqlc.narg(skill)))
+    AND ((sqlc.narg(min_monthly) IS NULL AND sqlc.narg(max_monthly) IS NULL AND sqlc.narg(currency) IS NULL) OR EXISTS (
+        SELECT 1 FROM job_offer_salaries
+        WHERE job_offer_salaries.job_offer_id = job_offers.id
+          AND (sqlc.narg(currency) IS NULL OR job_offer_salaries.currency = sqlc.narg(currency))
+          AND (sqlc.narg(min_monthly) IS NULL OR job_offer_salaries.monthly_max >= sqlc.narg(min_monthly))
+          AND (sqlc.narg(max_monthly) IS NULL OR job_offer_salaries.monthly_min <= sqlc.narg(max_monthly))))
+    AND (sqlc.arg(include_closed) OR closed_at IS NULL)
+)
+SELECT COUNT(*) FROM matching
+WHERE NOT sqlc.arg(one_per_group) OR group_rank = 1;
@@ -1,8 +1,8 @@
 -- name: CreateJobOffer :one
 INSERT INTO job_offers (
     id, title, company, location, description, url, source, published_at, skills,
-    salary_employment, salary_b2b, salary_contract
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    salary_employment, salary_b2b, salary_contract, work_mode, country
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdateJobOffer :one
@@ -17,54 +17,68 @@ SET
     salary_employment = ?,
     salary_b2b = ?,
     salary_contract = ?,
+    work_mode = ?,
+    country = ?,
     last_seen_at = CURRENT_TIMESTAMP
 WHERE id = ?
 RETURNING *;
 
 -- name: UpsertJobOffer :one
+-- a rescrape without dates keeps the ones found before
 INSERT INTO job_offers (
     id, title, company, location, description, url, source, published_at, skills,
-    salary_employment, salary_b2b, salary_contract
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    salary_employment, salary_b2b, salary_contract, work_mode, country, expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(url) DO UPDATE SET
     title = excluded.title,
     company = excluded.company,
     location = excluded.location,
     description = excluded.description,
-    published_at = excluded.published_at,
+    published_at = COALESCE(excluded.published_at, job_offers.published_at),
     skills = excluded.skills,
     salary_employment = excluded.salary_employment,
     salary_b2b = excluded.salary_b2b,
     salary_contract = excluded.salary_contract,
+    work_mode = excluded.work_mode,
+    country = excluded.country,
+    expires_at = COALESCE(excluded.expires_at, job_offers.expires_at),
+    closed_at = NULL,
     last_seen_at = CURRENT_TIMESTAMP
 RETURNING *;
 
 
+-- name: GetJobOffer :one
+SELECT * FROM job_offers
+WHERE id = ?;
+
 -- name: DeleteJobOffer :exec
 DELETE FROM job_offers WHERE id = ?;
 
 -- name: ListJobOffers :many
-SELECT * FROM job_offers 
-ORDER BY created_at DESC 
+SELECT * FROM job_offers
+WHERE closed_at IS NULL
+ORDER BY created_at DESC, rowid DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListRecentJobOffers :many
-SELECT * FROM job_offers 
-ORDER BY published_at DESC 
+SELECT * FROM job_offers
+WHERE closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
 LIMIT ?;
 
 -- name: ListJobOffersBySource :many
 SELECT * FROM job_offers 
-WHERE source = ?
-ORDER BY published_at DESC 
+WHERE source = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
 LIMIT ? OFFSET ?;
 
 -- name: ListJobOffersByCompany :many
 SELECT * FROM job_offers 
-WHERE company = ?
-ORDER BY published_at DESC;
+WHERE company_id = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC;
 
--- name: ListJobOffersByLocation :many
-SELECT * FROM job_offers 
-WHERE location LIKE ?
-ORDER BY published_at DESC;
+-- name: ListJobOffersByWorkMode :many
+SELECT * FROM job_offers
+WHERE work_mode = ? AND closed_at IS NULL
+ORDER BY COALESCE(published_at, created_at) DESC, rowid DESC
+LIMIT ? OFFSET ?;
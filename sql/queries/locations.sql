@@ -0,0 +1,25 @@
+-- name: DeleteJobOfferCities :exec
+DELETE FROM job_offer_cities WHERE job_offer_id = ?;
+
+-- name: InsertJobOfferCity :exec
+INSERT OR IGNORE INTO job_offer_cities (job_offer_id, city) VALUES (?, ?);
+
+-- name: ListJobOfferCities :many
+SELECT city FROM job_offer_cities
+WHERE job_offer_id = ?
+ORDER BY city;
+
+-- name: ListJobOffersByCity :many
+SELECT job_offers.* FROM job_offers
+JOIN job_offer_cities ON job_offer_cities.job_offer_id = job_offers.id
+WHERE job_offer_cities.city = ? AND job_offers.closed_at IS NULL
+ORDER BY COALESCE(job_offers.published_at, job_offers.created_at) DESC, job_offers.rowid DESC
+LIMIT ? OFFSET ?;
+
+-- name: ListJobOffersByCityAndWorkMode :many
+SELECT job_offers.* FROM job_offers
+JOIN job_offer_cities ON job_offer_cities.job_offer_id = job_offers.id
+WHERE job_offer_cities.city = ? AND job_offers.work_mode = ?
+  AND job_offers.closed_at IS NULL
+ORDER BY COALESCE(job_offers.published_at, job_offers.created_at) DESC, job_offers.rowid DESC
+LIMIT ? OFFSET ?;
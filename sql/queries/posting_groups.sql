@@ -0,0 +1,16 @@
+-- name: ListPostingCandidates :many
+-- what dedupe compares in insert order, so the first offer of a job becomes its canonical one
+SELECT id, title, company, description, work_mode, posting_group_id, closed_at FROM job_offers
+ORDER BY created_at, rowid;
+
+-- name: ListPostingCandidateCities :many
+SELECT job_offer_id, city FROM job_offer_cities
+ORDER BY job_offer_id, city;
+
+-- name: SetJobOfferPostingGroup :exec
+UPDATE job_offers SET posting_group_id = ? WHERE id = ?;
+
+-- name: ListPostingGroupOffers :many
+SELECT id, source, url, closed_at FROM job_offers
+WHERE posting_group_id = ?
+ORDER BY created_at, rowid;
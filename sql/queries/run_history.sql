@@ -0,0 +1,19 @@
+-- name: InsertRunHistory :one
+INSERT INTO run_history (started_at, finished_at, duration_ms, error)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: InsertRunHistorySource :exec
+INSERT INTO run_history_sources (
+    run_id, source, attempted, saved, closed, fetch_failed, parse_failed, save_failed, captchas
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListRunHistory :many
+SELECT * FROM run_history
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: ListRunHistorySources :many
+SELECT * FROM run_history_sources
+WHERE run_id = ?
+ORDER BY source;
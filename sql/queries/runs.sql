@@ -0,0 +1,40 @@
+-- name: CreateScrapeRun :one
+INSERT INTO scrape_runs (kind, source, run_key)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: DeleteOpenScrapeRuns :exec
+DELETE FROM scrape_runs
+WHERE kind = ? AND source = ? AND finished_at IS NULL;
+
+-- name: DeleteScrapeRunUrls :exec
+DELETE FROM scrape_run_urls WHERE run_id = ?;
+
+-- name: FinishScrapeRun :exec
+UPDATE scrape_runs
+SET finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetOpenScrapeRun :one
+SELECT * FROM scrape_runs
+WHERE kind = ? AND source = ? AND finished_at IS NULL
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: InsertScrapeRunUrl :exec
+INSERT OR IGNORE INTO scrape_run_urls (run_id, position, url) VALUES (?, ?, ?);
+
+-- name: ListScrapeRunUrls :many
+SELECT * FROM scrape_run_urls
+WHERE run_id = ?
+ORDER BY position;
+
+-- name: MarkScrapeRunUrlDone :exec
+UPDATE scrape_run_urls
+SET done_at = CURRENT_TIMESTAMP
+WHERE run_id = ? AND url = ?;
+
+-- name: UpdateScrapeRunPage :exec
+UPDATE scrape_runs
+SET page = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
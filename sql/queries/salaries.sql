@@ -0,0 +1,32 @@
+-- name: UpsertJobOfferSalary :exec
+INSERT INTO job_offer_salaries (
+    job_offer_id, contract_type, min_amount, max_amount, currency, period, gross,
+    monthly_min, monthly_max, raw
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(job_offer_id, contract_type) DO UPDATE SET
+    min_amount = excluded.min_amount,
+    max_amount = excluded.max_amount,
+    currency = excluded.currency,
+    period = excluded.period,
+    gross = excluded.gross,
+    monthly_min = excluded.monthly_min,
+    monthly_max = excluded.monthly_max,
+    raw = excluded.raw;
+
+-- name: DeleteJobOfferSalaries :exec
+DELETE FROM job_offer_salaries WHERE job_offer_id = ?;
+
+-- name: ListJobOfferSalaries :many
+SELECT * FROM job_offer_salaries
+WHERE job_offer_id = ?
+ORDER BY contract_type;
+
+-- name: ListJobOffersByMonthlySalary :many
+SELECT job_offers.* FROM job_offers
+JOIN job_offer_salaries ON job_offer_salaries.job_offer_id = job_offers.id
+WHERE job_offer_salaries.currency = ?
+  AND job_offer_salaries.monthly_max >= ?
+  AND job_offers.closed_at IS NULL
+GROUP BY job_offers.id
+ORDER BY MAX(job_offer_salaries.monthly_max) DESC
+LIMIT ? OFFSET ?;
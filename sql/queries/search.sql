@@ -0,0 +1,34 @@
+-- name: DeleteJobOfferSearch :exec
+DELETE FROM job_offers_fts WHERE job_offer_id = ?;
+
+-- name: InsertJobOfferSearch :exec
+INSERT INTO job_offers_fts (job_offer_id, title, company, description, skills)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: SearchJobOffers :many
+-- bm25 weights follow the fts columns: job_offer_id, title, company, description, skills
+SELECT sqlc.embed(job_offers),
+    CAST(snippet(job_offers_fts, -1, '[', ']', '…', 16) AS TEXT) AS snippet,
+    CAST(bm25(job_offers_fts, 0.0, 10.0, 5.0, 1.0, 4.0) AS REAL) AS rank
+FROM job_offers_fts
+JOIN job_offers ON job_offers.id = job_offers_fts.job_offer_id
+WHERE job_offers.closed_at IS NULL AND job_offers_fts MATCH sqlc.arg(query)
+ORDER BY rank
+LIMIT sqlc.arg(limit);
+
+-- name: ClearJobOffersSearch :exec
+DELETE FROM job_offers_fts;
+
+-- name: ListJobOffersForSearch :many
+SELECT id, title, company, description, skills FROM job_offers;
+
+-- name: GetSearchIndexStale :one
+SELECT stale FROM search_index_state WHERE id = 1;
+
+-- name: SetSearchIndexStale :exec
+UPDATE search_index_state SET stale = ? WHERE id = 1;
+
+-- name: MarkSearchIndexStale :execrows
+-- only an existing index goes stale, a database without one gets it built by the fts5 migration
+UPDATE search_index_state SET stale = 1
+WHERE id = 1 AND NOT stale AND EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'job_offers_fts');
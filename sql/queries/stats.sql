@@ -0,0 +1,33 @@
+-- name: CountJobOffersBySource :many
+SELECT source, COUNT(*) AS offers FROM job_offers
+WHERE closed_at IS NULL
+GROUP BY source
+ORDER BY offers DESC;
+
+-- name: CountJobOffersByCity :many
+SELECT city, COUNT(DISTINCT COALESCE(job_offers.posting_group_id, job_offers.id)) AS offers FROM job_offer_cities
+JOIN job_offers ON job_offers.id = job_offer_cities.job_offer_id
+WHERE job_offers.closed_at IS NULL
+GROUP BY city
+ORDER BY offers DESC, city
+LIMIT ?;
+
+-- name: CountJobOffersBySkill :many
+SELECT CAST(json_each.value AS TEXT) AS skill, COUNT(DISTINCT COALESCE(job_offers.posting_group_id, job_offers.id)) AS offers
+FROM job_offers, json_each(job_offers.skills)
+WHERE job_offers.closed_at IS NULL
+GROUP BY skill
+ORDER BY offers DESC, skill
+LIMIT ?;
+
+-- name: SalaryStatsByContract :many
+SELECT contract_type, currency, COUNT(DISTINCT COALESCE(job_offers.posting_group_id, job_offers.id)) AS offers,
+    CAST(AVG(monthly_min) AS REAL) AS avg_monthly_min,
+    CAST(AVG(monthly_max) AS REAL) AS avg_monthly_max,
+    CAST(MIN(monthly_min) AS REAL) AS min_monthly,
+    CAST(MAX(monthly_max) AS REAL) AS max_monthly
+FROM job_offer_salaries
+JOIN job_offers ON job_offers.id = job_offer_salaries.job_offer_id
+WHERE job_offers.closed_at IS NULL
+GROUP BY contract_type, currency
+ORDER BY contract_type, offers DESC;
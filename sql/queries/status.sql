@@ -0,0 +1,19 @@
+-- name: CloseJobOffer :exec
+UPDATE job_offers
+SET closed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND closed_at IS NULL;
+
+-- name: CloseJobOfferByUrl :execrows
+UPDATE job_offers
+SET closed_at = CURRENT_TIMESTAMP
+WHERE url = ? AND closed_at IS NULL;
+
+-- name: ListJobOfferStatesBySource :many
+SELECT id, url, closed_at, last_seen_at FROM job_offers
+WHERE source = ?;
+
+-- name: ReopenJobOffer :exec
+-- last_seen_at stays, it tells when the offer page was last scraped
+UPDATE job_offers
+SET closed_at = NULL
+WHERE id = ?;
@@ -0,0 +1,16 @@
+-- name: GetJobOfferByUrl :one
+SELECT * FROM job_offers
+WHERE url = ?;
+
+-- name: GetLatestJobOfferVersion :one
+SELECT CAST(COALESCE(MAX(version), 0) AS INTEGER) AS version FROM job_offer_versions
+WHERE job_offer_id = ?;
+
+-- name: InsertJobOfferVersion :exec
+INSERT INTO job_offer_versions (job_offer_id, version, field, old_value, new_value)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListJobOfferVersions :many
+SELECT * FROM job_offer_versions
+WHERE job_offer_id = ?
+ORDER BY version, field;
@@ -2,4 +2,5 @@
 ALTER TABLE job_offers ADD COLUMN embedding BLOB;
 
 -- +goose Down
-ALTER TABLE job_offers DROP COLUMN embedding BLOB;
+-- sqlite takes no type in DROP COLUMN, this Down never ran before migrate validated it; Up is unchanged
+ALTER TABLE job_offers DROP COLUMN embedding;
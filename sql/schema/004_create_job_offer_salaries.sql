@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS job_offer_salaries (
+    job_offer_id TEXT NOT NULL REFERENCES job_offers(id) ON DELETE CASCADE,
+    contract_type TEXT NOT NULL, -- employment, b2b, mandate
+    min_amount REAL NOT NULL,
+    max_amount REAL NOT NULL,
+    currency TEXT,
+    period TEXT NOT NULL, -- hour, day, month, year
+    gross BOOLEAN, -- NULL when the offer doesn't say
+    monthly_min REAL NOT NULL,
+    monthly_max REAL NOT NULL,
+    raw TEXT NOT NULL,
+    PRIMARY KEY (job_offer_id, contract_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_offer_salaries_monthly ON job_offer_salaries (currency, monthly_max);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_offer_salaries;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE job_offers ADD COLUMN work_mode TEXT; -- remote, hybrid, onsite
+ALTER TABLE job_offers ADD COLUMN country TEXT;
+
+CREATE TABLE IF NOT EXISTS job_offer_cities (
+    job_offer_id TEXT NOT NULL REFERENCES job_offers(id) ON DELETE CASCADE,
+    city TEXT NOT NULL,
+    PRIMARY KEY (job_offer_id, city)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_offer_cities_city ON job_offer_cities (city);
+CREATE INDEX IF NOT EXISTS idx_job_offers_work_mode ON job_offers (work_mode);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_offer_cities;
+DROP INDEX IF EXISTS idx_job_offers_work_mode;
+
+ALTER TABLE job_offers DROP COLUMN work_mode;
+ALTER TABLE job_offers DROP COLUMN country;
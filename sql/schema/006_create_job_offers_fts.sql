@@ -0,0 +1,23 @@
+-- +goose Up
+-- needs sqlite built with fts5 (go build -tags sqlite_fts5)
+-- rows are written by the collector with the description html stripped, offers stored
+-- before are indexed from Go the same way (see search_index_state)
+CREATE VIRTUAL TABLE IF NOT EXISTS job_offers_fts USING fts5(
+    job_offer_id UNINDEXED,
+    title,
+    company,
+    description,
+    skills,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS job_offers_fts_delete AFTER DELETE ON job_offers
+BEGIN
+    DELETE FROM job_offers_fts WHERE job_offer_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS job_offers_fts_delete;
+DROP TABLE IF EXISTS job_offers_fts;
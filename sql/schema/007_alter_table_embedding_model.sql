@@ -0,0 +1,10 @@
+-- +goose Up
+-- vectors from different embedders are not comparable, similarity only looks at rows of the current model
+ALTER TABLE job_offers ADD COLUMN embedding_model TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_job_offers_embedding_model ON job_offers (embedding_model);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_job_offers_embedding_model;
+
+ALTER TABLE job_offers DROP COLUMN embedding_model;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- one row per changed field, rows sharing a version were changed by the same rescrape
+CREATE TABLE IF NOT EXISTS job_offer_versions (
+    job_offer_id TEXT NOT NULL REFERENCES job_offers(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_offer_id, version, field)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_offer_versions_field ON job_offer_versions (field, changed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_offer_versions;
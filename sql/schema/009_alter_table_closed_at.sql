@@ -0,0 +1,10 @@
+-- +goose Up
+-- set when an offer disappears from the board listing or its page says it expired, cleared when it shows up again
+ALTER TABLE job_offers ADD COLUMN closed_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_job_offers_source_closed ON job_offers (source, closed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_job_offers_source_closed;
+
+ALTER TABLE job_offers DROP COLUMN closed_at;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- progress of collect-urls and scrape runs, an unfinished run is resumed by the next one of the same kind and source
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- 'collect' or 'scrape'
+    kind TEXT NOT NULL,
+    source TEXT NOT NULL,
+    -- start url of a collect run, hash of the url list of a scrape run
+    run_key TEXT NOT NULL,
+    -- last listing page a collect run finished
+    page INTEGER NOT NULL DEFAULT 0,
+    started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_open ON scrape_runs (kind, source, finished_at);
+
+-- urls collected so far or to scrape, dropped when the run finishes
+CREATE TABLE IF NOT EXISTS scrape_run_urls (
+    run_id INTEGER NOT NULL REFERENCES scrape_runs(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    done_at DATETIME,
+    PRIMARY KEY (run_id, url)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS scrape_run_urls;
+DROP TABLE IF EXISTS scrape_runs;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- one row per scrape command, kept after the run unlike scrape_runs progress
+CREATE TABLE IF NOT EXISTS run_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    -- what the run ended with, NULL when it completed
+    error TEXT
+);
+
+-- what happened to the urls of each source during a run
+CREATE TABLE IF NOT EXISTS run_history_sources (
+    run_id INTEGER NOT NULL REFERENCES run_history(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    attempted INTEGER NOT NULL DEFAULT 0,
+    saved INTEGER NOT NULL DEFAULT 0,
+    closed INTEGER NOT NULL DEFAULT 0,
+    fetch_failed INTEGER NOT NULL DEFAULT 0,
+    parse_failed INTEGER NOT NULL DEFAULT 0,
+    save_failed INTEGER NOT NULL DEFAULT 0,
+    captchas INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (run_id, source)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS run_history_sources;
+DROP TABLE IF EXISTS run_history;
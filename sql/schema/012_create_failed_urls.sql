@@ -0,0 +1,18 @@
+-- +goose Up
+-- urls a scrape could not turn into an offer, retry-failed picks the transient ones up again
+CREATE TABLE IF NOT EXISTS failed_urls (
+    url TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    error TEXT NOT NULL,
+    -- the page is gone (404/410), retrying won't help
+    permanent BOOLEAN NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at DATETIME NOT NULL,
+    -- the next retry waits scrape.retry_failed.backoff doubled with every attempt
+    last_attempt_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_urls_source ON failed_urls (source);
+
+-- +goose Down
+DROP TABLE IF EXISTS failed_urls;
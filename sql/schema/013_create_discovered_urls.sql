@@ -0,0 +1,17 @@
+-- +goose Up
+-- offer urls found by collect-urls, the url list scrape works through
+CREATE TABLE IF NOT EXISTS discovered_urls (
+    -- normalized the same way as job_offers.url
+    url TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    first_discovered_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_discovered_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- 'pending' until the first scrape, then 'scraped' or 'failed'; 'closed' once it left the listing or expired
+    status TEXT NOT NULL DEFAULT 'pending',
+    scraped_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_discovered_urls_source ON discovered_urls (source, first_discovered_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS discovered_urls;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- the date the board says the offer stops being valid, published_at holds the scraped publication date
+ALTER TABLE job_offers ADD COLUMN expires_at DATETIME;
+
+-- +goose Down
+ALTER TABLE job_offers DROP COLUMN expires_at;
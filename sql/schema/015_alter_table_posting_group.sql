@@ -0,0 +1,11 @@
+-- +goose Up
+-- offers of the same job on several boards share the id of the group's canonical offer,
+-- NULL until dedupe ran, such an offer is a group of its own
+ALTER TABLE job_offers ADD COLUMN posting_group_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_job_offers_posting_group ON job_offers (posting_group_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_job_offers_posting_group;
+
+ALTER TABLE job_offers DROP COLUMN posting_group_id;
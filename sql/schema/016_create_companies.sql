@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS companies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- spelling of the first offer seen
+    name TEXT NOT NULL,
+    -- lowercase without the legal form, "ACME Sp. z o.o." and "Acme S.A." are both "acme"
+    normalized_name TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- every spelling a board used for the company
+CREATE TABLE IF NOT EXISTS company_aliases (
+    alias TEXT PRIMARY KEY,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON DELETE CASCADE
+);
+
+-- the company page of each board
+CREATE TABLE IF NOT EXISTS company_profiles (
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    url TEXT NOT NULL,
+    PRIMARY KEY (company_id, source)
+);
+
+-- NULL for offers without a company, offers stored before are linked by the next scrape
+ALTER TABLE job_offers ADD COLUMN company_id INTEGER REFERENCES companies(id);
+
+CREATE INDEX IF NOT EXISTS idx_company_aliases_company ON company_aliases (company_id);
+CREATE INDEX IF NOT EXISTS idx_job_offers_company ON job_offers (company_id, closed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_job_offers_company;
+
+ALTER TABLE job_offers DROP COLUMN company_id;
+
+DROP TABLE IF EXISTS company_profiles;
+DROP TABLE IF EXISTS company_aliases;
+DROP TABLE IF EXISTS companies;
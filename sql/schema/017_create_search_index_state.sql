@@ -0,0 +1,13 @@
+-- +goose Up
+-- stale until the index of job_offers_fts matches the offers, set by builds without fts5 that
+-- saved offers they could not index, the next fts5 build reindexes every offer from Go
+CREATE TABLE IF NOT EXISTS search_index_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    stale BOOLEAN NOT NULL DEFAULT 0
+);
+
+-- indexes built before hold the raw description html, rebuild them once
+INSERT INTO search_index_state (id, stale) VALUES (1, 1);
+
+-- +goose Down
+DROP TABLE IF EXISTS search_index_state;
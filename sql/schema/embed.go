@@ -0,0 +1,7 @@
+// Package schema holds the goose migrations of the database, embedded so the binary can apply them
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,23 @@
+package urlsgocraper
+
+import (
+	"context"
+	"log"
+)
+
+// Checkpoint keeps collector progress between runs so an interrupted collect can resume,
+// implemented by iternal.Run
+type Checkpoint interface {
+	// last listing page saved by an interrupted run, 0 when starting fresh
+	Page() int
+	// urls saved so far
+	URLs() []string
+	Save(ctx context.Context, page int, found []string) error
+}
+
+// a failed save only costs the progress, collecting goes on
+func saveCheckpoint(ctx context.Context, cp Checkpoint, page int, found []string) {
+	if err := cp.Save(ctx, page, found); err != nil {
+		log.Printf("Error %v while saving collect progress", err)
+	}
+}
@@ -5,13 +5,12 @@ import (
 	"fmt"
 
 	"log"
-	"math/rand"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
 
 	//"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/chromedp"
@@ -20,9 +19,6 @@ import (
 //browser session data dir
 
 const (
-	minTimeMs             = 3000
-	maxTimeMs             = 4000
-	justjoinsource        = "https://justjoin.it/job-offers/" //all-locations/html
 	justjoinprefix        = "https://justjoin.it/"
 	justjoinofferSelector = "a.offer-card"
 )
@@ -46,45 +42,21 @@ func getJustJoinJtUrlsFromContent(html string) ([]string, error) {
 	return urls, nil
 }
 
-func JustJoinScrollAndRead(parentCtx context.Context) ([]string, error) {
+// start url (e.g. https://justjoin.it/job-offers/all-locations/html) and pacing come from cfg,
+// scrolling can't skip ahead so a resumed run starts from the top, only urls seen while scrolling
+// are returned as listed, the ones an interrupted run saved in cp may be gone since
+func JustJoinScrollAndRead(parentCtx context.Context, b fetcher.Browser, cfg config.Source, cp Checkpoint) ([]string, error) {
+	limiter := collectLimiter(cfg)
 	var urls []string
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.ExecPath(config.BrowserDir),
-		chromedp.UserDataDir(config.JustjoinDataDir),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("headless", false),
-		chromedp.Flag("disable-gpu", false),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36"),
-		chromedp.Flag("disable-web-security", true),
-		//flags for ANR
-		chromedp.Flag("disable-web-security", true),
-		chromedp.Flag("disable-site-isolation-trials", true),
-		chromedp.Flag("disable-background-timer-throttling", true),
-		chromedp.Flag("disable-renderer-backgrounding", true),
-		chromedp.Flag("disable-backgrounding-occluded-windows", true),
-		chromedp.Flag("disable-ipc-flooding-protection", true),
-		//chromedp.Flag("disable-gpu-compositing", true),
-	)
-
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(parentCtx, opts...)
-	defer cancelAlloc()
-
-	chromeDpCtx, cancelCtx := chromedp.NewContext(allocCtx)
-	defer cancelCtx()
-	//justjoin scraping often crashes, defer for rescuing data
-	defer func() {
-		urls = UniqueSliceElements(urls)
-	}()
-
 	log.Println("JUSTJOINIT: Uruchamianie przeglądarki...")
 
-	_ = chromedp.Run(chromeDpCtx,
+	runErr := b.Run(parentCtx,
 
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			return emulation.SetDeviceMetricsOverride(1280, 900, 1.0, false).Do(ctx)
 		}),
-		chromedp.Navigate(justjoinsource),
+		chromedp.Navigate(cfg.StartURL),
 		chromedp.Evaluate(`delete navigator.__proto__.webdriver`, nil),
 		chromedp.WaitVisible(`body`, chromedp.ByQuery),
 
@@ -113,21 +85,22 @@ func JustJoinScrollAndRead(parentCtx context.Context) ([]string, error) {
 					break
 				}
 
+				// the list only renders the cards in view, a screen not read is lost
 				if err := chromedp.OuterHTML("html", &html).Do(ctx); err != nil {
-					log.Printf("Błąd odczytu HTML: %v", err)
-				} else {
-					collected, err := getJustJoinJtUrlsFromContent(html)
-					if err == nil {
-						urls = append(urls, collected...)
-						log.Printf("JUSTJOINIT: Iteracja %d: Znaleziono %d linków (razem: %d)", i, len(collected), len(urls))
-					}
+					return fmt.Errorf("błąd odczytu HTML: %w", err)
 				}
+				collected, err := getJustJoinJtUrlsFromContent(html)
+				if err != nil {
+					return err
+				}
+				urls = append(urls, collected...)
+				saveCheckpoint(ctx, cp, 0, collected)
+				log.Printf("JUSTJOINIT: Iteracja %d: Znaleziono %d linków (razem: %d)", i, len(collected), len(urls))
 
 				prevHeight = currentHeight
 				log.Printf("JUSTJOINIT: Scrollowanie do: %d", currentHeight)
 
-				randomDelay := rand.Intn(maxTimeMs-minTimeMs) + minTimeMs
-				err = chromedp.Sleep(time.Duration(randomDelay) * time.Millisecond).Do(ctx)
+				err = limiter.Wait(ctx)
 				if err != nil {
 					return err
 				}
@@ -140,8 +113,7 @@ func JustJoinScrollAndRead(parentCtx context.Context) ([]string, error) {
 					return err
 				}
 
-				randomDelay = rand.Intn(maxTimeMs-minTimeMs) + minTimeMs
-				err = chromedp.Sleep(time.Duration(randomDelay) * time.Millisecond).Do(ctx)
+				err = limiter.Wait(ctx)
 				if err != nil {
 					return err
 				}
@@ -149,6 +121,10 @@ func JustJoinScrollAndRead(parentCtx context.Context) ([]string, error) {
 			return nil
 		}),
 	)
+	// saved progress stays for the next run
+	if runErr != nil {
+		return nil, runErr
+	}
 
 	urls = UniqueSliceElements(urls)
 	log.Printf("JUSTJOINIT: Usunięto duplikaty, %v unikalnych linków", len(urls))
@@ -0,0 +1,17 @@
+package urlsgocraper
+
+import (
+	"net/url"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+)
+
+// scroll collectors drive the page themselves, they wait on the start url host's limiter between steps
+func collectLimiter(cfg config.Source) *fetcher.Limiter {
+	host := cfg.StartURL
+	if u, err := url.Parse(cfg.StartURL); err == nil {
+		host = u.Host
+	}
+	return fetcher.LimiterFor(host, cfg.CollectRateLimit())
+}
@@ -3,7 +3,6 @@ package urlsgocraper
 import (
 	"context"
 	"log"
-	"math/rand"
 	"strings"
 	"time"
 
@@ -12,17 +11,17 @@ import (
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/chromedp"
 	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
 )
 
 const (
-	// tylko do testow
-	nofluffsource = "https://nofluffjobs.com/pl/" //JavaScript?criteria=requirement%3DUML
 	nofluffprefix = "https://nofluffjobs.com"
 	//nofluffsource        = "https://nofluffjobs.com/pl/artificial-intelligence?criteria=category%3Dsys-administrator,business-analyst,architecture,backend,data,ux,devops,erp,embedded,frontend,fullstack,game-dev,mobile,project-manager,security,support,testing,other"
 	nofluffofferSelector = "a.posting-list-item"
 	//nofluffcookiesButtonSelector = "button#save"                                // zamknięcie cookies
 	//noflufloginButtonSelector    = "button[.//inline-icon[@maticon=\"close\"]]" // zamknięcie prośby o zalogowanie
 	nofluffloadMoreSelector = "button[nfjloadmore]"
+	nofluffCheckpointEvery  = 10
 )
 
 func UniqueSliceElements[T comparable](inputSlice []T) []T {
@@ -56,43 +55,21 @@ func getNoFluffUrlsFromContent(html string) ([]string, error) {
 	return urls, nil
 }
 
-func NofluffScrollAndRead(parentCtx context.Context) ([]string, error) {
-	var urls []string
-
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.ExecPath(config.BrowserDir),
-		chromedp.UserDataDir(config.NofluffDataDir),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("headless", false),
-		chromedp.Flag("disable-gpu", false),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36"),
-		chromedp.Flag("disable-web-security", true),
-				//flags for ANR
-		chromedp.Flag("disable-web-security", true),
-		chromedp.Flag("disable-site-isolation-trials", true),
-		chromedp.Flag("disable-background-timer-throttling", true), 
-		chromedp.Flag("disable-renderer-backgrounding", true),
-		chromedp.Flag("disable-backgrounding-occluded-windows", true),
-		chromedp.Flag("disable-ipc-flooding-protection", true),
-		chromedp.Flag("disable-gpu-compositing", true),
-	)
-
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(parentCtx, opts...)
-	defer cancelAlloc()
-
-	chromeDpCtx, cancelCtx := chromedp.NewContext(allocCtx)
-	defer cancelCtx()
-
+// start url (e.g. https://nofluffjobs.com/pl/JavaScript?criteria=requirement%3DUML for testing) and pacing come from cfg,
+// "load more" can't skip ahead so a resumed run starts from the top, only urls of the finished list
+// are returned as listed, the ones an interrupted run saved in cp may be gone since
+func NofluffScrollAndRead(parentCtx context.Context, b fetcher.Browser, cfg config.Source, cp Checkpoint) ([]string, error) {
+	limiter := collectLimiter(cfg)
 	log.Println("NOFLUFFJOBS: Uruchamianie przeglądarki...")
 
 	var html string
 
-	err := chromedp.Run(chromeDpCtx,
+	runErr := b.Run(parentCtx,
 
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			return emulation.SetDeviceMetricsOverride(1280, 900, 1.0, false).Do(ctx)
 		}),
-		chromedp.Navigate(nofluffsource),
+		chromedp.Navigate(cfg.StartURL),
 		chromedp.Evaluate(`delete navigator.__proto__.webdriver`, nil),
 		chromedp.WaitVisible(`body`, chromedp.ByQuery),
 		//klika wymagane cookies jeśli jest komunikat, blokuje program jeśli ich nie ma :/
@@ -110,8 +87,7 @@ func NofluffScrollAndRead(parentCtx context.Context) ([]string, error) {
 
 			for i := 1; ; i++ {
 				log.Printf("NOFLUFFJOBS: Iteracja: %v", i)
-				randomDelay := rand.Intn(maxTimeMs-minTimeMs) + minTimeMs
-				err := chromedp.Sleep(time.Duration(randomDelay) * time.Millisecond).Do(ctx)
+				err := limiter.Wait(ctx)
 				if err != nil {
 					return err
 				}
@@ -129,8 +105,20 @@ func NofluffScrollAndRead(parentCtx context.Context) ([]string, error) {
 					return err
 				}
 
-				randomDelay = rand.Intn((maxTimeMs+10*i)-(minTimeMs+10*i)) + (minTimeMs + 10*i) // czym więcej kontentu (kolejne iteracje) tym dłużej czekamy (wolniejsza strona)
-				err = chromedp.Sleep(time.Duration(randomDelay) * time.Millisecond).Do(ctx)
+				// reading the whole list is slow, save progress every few clicks
+				if i%nofluffCheckpointEvery == 0 {
+					var partial string
+					if err := chromedp.OuterHTML("html", &partial).Do(ctx); err == nil {
+						if found, err := getNoFluffUrlsFromContent(partial); err == nil {
+							saveCheckpoint(ctx, cp, 0, found)
+						}
+					}
+				}
+
+				if err := limiter.Wait(ctx); err != nil {
+					return err
+				}
+				err = chromedp.Sleep(time.Duration(10*i) * time.Millisecond).Do(ctx) // czym więcej kontentu (kolejne iteracje) tym dłużej czekamy (wolniejsza strona)
 				if err != nil {
 					return err
 				}
@@ -139,7 +127,11 @@ func NofluffScrollAndRead(parentCtx context.Context) ([]string, error) {
 		}),
 		chromedp.OuterHTML("html", &html),
 	)
-	urls, err = getNoFluffUrlsFromContent(html)
+	// saved progress stays for the next run
+	if runErr != nil {
+		return nil, runErr
+	}
+	urls, err := getNoFluffUrlsFromContent(html)
 	urls = UniqueSliceElements(urls)
 	log.Printf("NOFLUFFJOBS: Usunięto duplikaty, %v unikalnych linków", len(urls))
 	if err != nil {
@@ -2,47 +2,16 @@ package urlsgocraper
 
 import (
 	"context"
+	"fmt"
 	"github.com/PuerkitoBio/goquery"
-	"github.com/chromedp/cdproto/emulation"
-	"github.com/chromedp/chromedp"
 	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
 	"log"
-	"math/rand"
+	"net/url"
 	"strconv"
 	"strings"
-	"time"
 )
 
-//browser session data dir
-
-const (
-	minTimeS = 5
-	maxTimeS = 10
-)
-
-func getHTMLContent(chromeDpCtx context.Context, url string) (string, error) {
-	var html string
-
-	//chromdp run config
-	err := chromedp.Run(
-		chromeDpCtx,
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			return emulation.SetDeviceMetricsOverride(1280, 900, 1.0, false).Do(ctx)
-		}),
-		chromedp.Navigate(url),
-		chromedp.Evaluate(`delete navigator.__proto__.webdriver`, nil),
-		chromedp.Evaluate(`Object.defineProperty(navigator, "webdriver", { get: () => false })`, nil),
-		chromedp.Sleep(time.Duration(rand.Intn(800)+300)*time.Millisecond),
-		chromedp.WaitVisible("body", chromedp.ByQuery),
-		chromedp.OuterHTML("html", &html),
-	)
-	if err != nil {
-		log.Printf("Error %s", err)
-	}
-
-	return html, err
-}
-
 func getUrlsFromContent(html, selector string) ([]string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
@@ -79,78 +48,66 @@ func getMaxPagePracujPl(html string) (int, error) {
 	return maxPageNum, nil
 }
 
-func CollectPracujPl(ctx context.Context) []string {
-	source := "https://it.pracuj.pl/praca" //?its=agile
+// start url (e.g. https://it.pracuj.pl/praca?its=agile) comes from cfg, f paces the listing pages,
+// listing pages already saved in cp are skipped. A page that fails ends the collect with an error,
+// the pages before it stay in cp for the next run.
+func CollectPracujPl(ctx context.Context, f fetcher.Fetcher, cfg config.Source, cp Checkpoint) ([]string, error) {
+	source := cfg.StartURL
 	urlsSelector := "[data-test=\"link-offer\"]"
-	var urls []string
-
-	//chromdp config
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.ExecPath(config.BrowserDir),
-		chromedp.UserDataDir(config.PracujDataDir),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("headless", false),
-		chromedp.Flag("disable-gpu", false),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) "+
-			"AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36"),
-		//chromedp.Flag("proxy-server", proxyList[rand.Intn(len(proxyList))]),
-
-		//flags for ANR
-		chromedp.Flag("disable-web-security", true),
-		chromedp.Flag("disable-site-isolation-trials", true),
-		chromedp.Flag("disable-background-timer-throttling", true), 
-		chromedp.Flag("disable-renderer-backgrounding", true),
-		chromedp.Flag("disable-backgrounding-occluded-windows", true),
-		chromedp.Flag("disable-ipc-flooding-protection", true),
-		chromedp.Flag("disable-gpu-compositing", true),
-	)
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancelAlloc()
-
-	chromeDpCtx, cancelCtx := chromedp.NewContext(allocCtx)
-	defer cancelCtx()
+	urls := append([]string(nil), cp.URLs()...)
+	if cp.Page() > 0 {
+		log.Printf("Resuming after page %d with %d urls", cp.Page(), len(urls))
+	}
+	// the page number joins the filters of the start url
+	listing, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing start url: %w", err)
+	}
 
-	html, err := getHTMLContent(chromeDpCtx, source)
+	page, err := f.Fetch(ctx, source)
 	if err != nil {
-		log.Printf("Error getting HTML content")
+		return nil, fmt.Errorf("getting first page: %w", err)
 	}
+	html := page.HTML
 
 	maxPage, err := getMaxPagePracujPl(html)
 	if err != nil {
-		log.Printf("Error getting max page")
+		return nil, fmt.Errorf("getting max page: %w", err)
 	}
 
-	firstPageUrls, err := getUrlsFromContent(html, urlsSelector)
-	if err != nil {
-		log.Printf("Error getting main page urls")
-	} else {
+	if cp.Page() == 0 {
+		firstPageUrls, err := getUrlsFromContent(html, urlsSelector)
+		if err != nil {
+			return nil, fmt.Errorf("getting first page urls: %w", err)
+		}
 		urls = append(urls, firstPageUrls...)
+		saveCheckpoint(ctx, cp, 1, firstPageUrls)
 		log.Printf("Scraped first page")
 	}
 	//for testing
 	//return firstPageUrls[:20]
 
-	if maxPage > 2 {
-		for i := 2; i < maxPage; i++ {
-			html, err = getHTMLContent(chromeDpCtx, source+"?pn="+strconv.Itoa(i))
-			if err != nil {
-				log.Printf("Error {%v} while getting HTML content on page: %v", err, i)
-			}
-			freshUrls, err1 := getUrlsFromContent(html, urlsSelector)
-			if err1 != nil {
-				log.Printf("Error {%v} while getting urls on page: %v", err, i)
-			} else {
-				urls = append(urls, freshUrls...)
-				log.Printf("Scraped page number: %v", i)
-
-			}
-			randomDelay := rand.Intn(maxTimeS-minTimeS) + minTimeS
-			log.Printf("Sleeping for: %ds", randomDelay)
-			time.Sleep(time.Duration(randomDelay) * time.Second)
+	for i := max(2, cp.Page()+1); i <= maxPage; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		query := listing.Query()
+		query.Set("pn", strconv.Itoa(i))
+		listing.RawQuery = query.Encode()
+		page, err = f.Fetch(ctx, listing.String())
+		if err != nil {
+			return nil, fmt.Errorf("getting page %d: %w", i, err)
 		}
+		freshUrls, err := getUrlsFromContent(page.HTML, urlsSelector)
+		if err != nil {
+			return nil, fmt.Errorf("getting urls on page %d: %w", i, err)
+		}
+		urls = append(urls, freshUrls...)
+		saveCheckpoint(ctx, cp, i, freshUrls)
+		log.Printf("Scraped page number: %v", i)
 	}
 	log.Printf("Collected: %d urls", len(urls))
-	return urls
+	return urls, nil
 }
 
 /*
@@ -0,0 +1,64 @@
+package urlsgocraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pfczx/jobscraper/config"
+	"github.com/pfczx/jobscraper/iternal/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serves listing pages by url, anything else is a 404
+type pagesFetcher map[string]string
+
+func (f pagesFetcher) Fetch(_ context.Context, url string) (fetcher.Page, error) {
+	html, ok := f[url]
+	if !ok {
+		return fetcher.Page{}, &fetcher.StatusError{URL: url, StatusCode: 404}
+	}
+	return fetcher.Page{HTML: html, FinalURL: url, StatusCode: 200}, nil
+}
+
+func (f pagesFetcher) Close() error { return nil }
+
+type memCheckpoint struct {
+	page int
+	urls []string
+}
+
+func (c *memCheckpoint) Page() int      { return c.page }
+func (c *memCheckpoint) URLs() []string { return c.urls }
+func (c *memCheckpoint) Save(_ context.Context, page int, found []string) error {
+	c.page = page
+	c.urls = append(c.urls, found...)
+	return nil
+}
+
+func listingPage(maxPage int, offers ...string) string {
+	html := fmt.Sprintf(`<span data-test="top-pagination-max-page-number">%d</span>`, maxPage)
+	for _, offer := range offers {
+		html += fmt.Sprintf(`<a data-test="link-offer" href="%s">offer</a>`, offer)
+	}
+	return html
+}
+
+func TestCollectPracujPlKeepsStartURLFilters(t *testing.T) {
+	f := pagesFetcher{
+		"https://it.pracuj.pl/praca?its=agile":      listingPage(3, "https://www.pracuj.pl/praca/a,oferta,1"),
+		"https://it.pracuj.pl/praca?its=agile&pn=2": listingPage(3, "https://www.pracuj.pl/praca/b,oferta,2"),
+		"https://it.pracuj.pl/praca?its=agile&pn=3": listingPage(3, "https://www.pracuj.pl/praca/c,oferta,3"),
+	}
+	cp := &memCheckpoint{}
+
+	urls, err := CollectPracujPl(context.Background(), f, config.Source{StartURL: "https://it.pracuj.pl/praca?its=agile"}, cp)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://www.pracuj.pl/praca/a,oferta,1",
+		"https://www.pracuj.pl/praca/b,oferta,2",
+		"https://www.pracuj.pl/praca/c,oferta,3",
+	}, urls)
+	assert.Equal(t, 3, cp.Page())
+}
@@ -6,7 +6,7 @@ import (
 )
 func SaveUrls(filename string,data []string) error {
 	content := strings.Join(data,"\n")
-	return os.WriteFile("./"+filename,[]byte(content),0666)
+	return os.WriteFile(filename,[]byte(content),0666)
 }
 
 func LoadUrls(filename string) ([]string,error){